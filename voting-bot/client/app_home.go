@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/slack-go/slack/slackevents"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/jobs"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// handleInnerEvent publishes the App Home when a user opens it. The user is
+// stored, so the App Home is refreshed whenever a cron job completes. Users
+// without the viewer role are shown a notice instead.
+func handleInnerEvent(ctx types.Context, evt interface{}) {
+	event, ok := evt.(*slackevents.AppHomeOpenedEvent)
+	if !ok || event.Tab != "home" {
+		return
+	}
+
+	// the event is acknowledged once the handler returns
+	go func() {
+		if err := publishAppHome(ctx, event.User); err != nil {
+			log.Printf("failed to publish App Home: %v", err)
+		}
+	}()
+}
+
+// publishAppHome publishes the overview to the user if the user may view it
+func publishAppHome(ctx types.Context, userID string) error {
+	allowed, err := canViewAppHome(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return jobs.RevokeAppHome(ctx, userID)
+	}
+
+	if err := ctx.Database().AddAppHomeUser(userID); err != nil {
+		log.Printf("failed to store App Home user %s: %v", userID, err)
+	}
+
+	return jobs.PublishAppHomeView(ctx, userID, jobs.AppHomeBlocks(ctx))
+}
+
+// AppHomeViewer returns the check the cron jobs run before refreshing the
+// App Home of a user, so that users whose role was revoked stop receiving it
+func AppHomeViewer(ctx types.Context) func(userID string) (bool, error) {
+	return func(userID string) (bool, error) {
+		return canViewAppHome(ctx, userID)
+	}
+}
+
+// canViewAppHome returns true if the user has the viewer role, or if
+// authorization is disabled. An error is returned if the role is not found
+// and the user groups could not be fetched, so that the user is not revoked
+// for a failure of Slack.
+func canViewAppHome(ctx types.Context, userID string) (bool, error) {
+	if !ctx.Config().Authorization.Enabled() {
+		return true, nil
+	}
+
+	role, err := getUserRole(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the role of %s: %v", userID, err)
+	}
+
+	return role.Allows(config.RoleViewer), nil
+}
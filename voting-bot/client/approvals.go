@@ -0,0 +1,261 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// approveVoteRequest stores the approval of the user and executes the vote
+// once the request has the required number of approvals. The vote of a
+// scheduled vote request is executed at the scheduled time.
+func approveVoteRequest(ctx types.Context, id int64, userID string, response slacker.ResponseWriter) error {
+	db := ctx.Database()
+	approval := ctx.Config().Approval
+
+	req, err := db.GetVoteRequest(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPending(ctx, req); err != nil {
+		return err
+	}
+
+	if userID == req.RequestedBy {
+		return fmt.Errorf("vote request %d can not be approved by its requester", id)
+	}
+
+	if !approval.IsApprover(userID) {
+		voting.AuditVoteRequest(ctx, id, userID, "approval denied", "user is not an approver")
+		return fmt.Errorf("<@%s> is not allowed to approve votes", userID)
+	}
+
+	approvals, err := db.AddVoteApproval(id, userID)
+	if err != nil {
+		return err
+	}
+
+	voting.AuditVoteRequest(ctx, id, userID, "approved", fmt.Sprintf("%d/%d approvals", approvals, approval.RequiredApprovals))
+	response.Reply(fmt.Sprintf("Vote request *#%d* approved by <@%s> (%d/%d approvals)", id, userID, approvals, approval.RequiredApprovals))
+
+	if approvals < approval.RequiredApprovals {
+		return nil
+	}
+
+	// concurrent approvals must not execute the vote twice
+	updated, err := db.UpdateVoteRequestStatus(id, database.VoteRequestPending, database.VoteRequestApproved)
+	if err != nil {
+		return fmt.Errorf("failed to update vote request %d: %v", id, err)
+	}
+	if !updated {
+		return nil
+	}
+
+	// scheduled votes are executed at their time
+	scheduled, found, err := db.GetScheduledVoteByRequest(id)
+	if err != nil {
+		log.Printf("failed to get scheduled vote of vote request %d: %v", id, err)
+	}
+	if found {
+		return response.Reply(fmt.Sprintf("Vote request *#%d* is approved, scheduled vote *#%d* is executed at %s",
+			id, scheduled.ID, time.Unix(scheduled.ExecuteAt, 0).UTC().Format(time.RFC822)))
+	}
+
+	status, details := database.VoteRequestExecuted, voting.DescribeVoteRequest(req)
+	if err := voting.ExecVoteRequest(ctx, req, false, response); err != nil {
+		status, details = database.VoteRequestFailed, err.Error()
+	}
+
+	if _, err := db.UpdateVoteRequestStatus(id, database.VoteRequestApproved, status); err != nil {
+		log.Printf("failed to update vote request %d: %v", id, err)
+	}
+	voting.AuditVoteRequest(ctx, id, userID, status, details)
+
+	return nil
+}
+
+// Commands of the confirmation buttons in the audit log
+const (
+	confirmVoteCommand = "confirm-vote"
+	cancelVoteCommand  = "cancel-vote"
+)
+
+// handleConfirmationButton broadcasts or cancels a simulated vote when its
+// requester clicks the Confirm or Cancel button. The outcome replaces the
+// buttons and the vote is posted in the thread of the message.
+func handleConfirmationButton(ctx types.Context, callback *slack.InteractionCallback, action *slack.BlockAction) (err error) {
+	db := ctx.Database()
+	userID := callback.User.ID
+	confirm := action.ActionID == voting.ConfirmVoteActionID
+
+	entry := database.AuditEntry{UserID: userID, Command: cancelVoteCommand, Params: "#" + action.Value}
+	if confirm {
+		entry.Command = confirmVoteCommand
+	}
+	response := voting.NewAuditWriter(callbackThreadWriter(ctx, callback))
+	denied := false
+	defer func() {
+		entry = response.Entry(entry)
+		if err != nil {
+			entry.Result = fmt.Sprintf("%s: %v", database.AuditResultError, err)
+		}
+		if denied {
+			entry.Result = database.AuditResultDenied
+		}
+		voting.AddAuditEntry(ctx, entry)
+	}()
+
+	id, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid vote request id: %s", action.Value)
+	}
+
+	req, err := db.GetVoteRequest(id)
+	if err != nil {
+		return err
+	}
+	if chainName, _, _, err := voting.GetVoteTargets(ctx, req.Target); err == nil {
+		entry.ChainName = chainName
+	}
+
+	if userID != req.RequestedBy {
+		return fmt.Errorf("only <@%s> can confirm or cancel vote request %d", req.RequestedBy, id)
+	}
+
+	if !isAuthorized(ctx, userID, config.RoleVoter, fmt.Sprintf("%s %d", entry.Command, id)) {
+		denied = true
+		return fmt.Errorf("you are not authorized to vote")
+	}
+
+	if req.Status != database.VoteRequestUnconfirmed {
+		return fmt.Errorf("vote request %d is %s", id, req.Status)
+	}
+
+	blockID := voting.ConfirmationBlockID(id)
+	if time.Now().UTC().Unix() >= req.ExpiresAt {
+		if _, err := db.UpdateVoteRequestStatus(id, database.VoteRequestUnconfirmed, database.VoteRequestExpired); err != nil {
+			log.Printf("failed to update vote request %d: %v", id, err)
+		}
+		voting.AuditVoteRequest(ctx, id, userID, database.VoteRequestExpired, "confirmation timeout passed")
+		if err := updateVoteButtons(ctx, callback, blockID, ":hourglass: The confirmation timed out, run the vote again"); err != nil {
+			log.Printf("failed to update confirmation of vote request %d: %v", id, err)
+		}
+		return fmt.Errorf("vote request %d is expired", id)
+	}
+
+	if !confirm {
+		updated, err := db.UpdateVoteRequestStatus(id, database.VoteRequestUnconfirmed, database.VoteRequestCancelled)
+		if err != nil {
+			return fmt.Errorf("failed to update vote request %d: %v", id, err)
+		}
+		if !updated {
+			return fmt.Errorf("vote request %d is no longer unconfirmed", id)
+		}
+
+		voting.AuditVoteRequest(ctx, id, userID, database.VoteRequestCancelled, voting.DescribeVoteRequest(req))
+		return updateVoteButtons(ctx, callback, blockID, fmt.Sprintf(":x: <@%s> cancelled %s", userID, voting.DescribeVoteRequest(req)))
+	}
+
+	// a second click must not broadcast the vote twice
+	updated, err := db.UpdateVoteRequestStatus(id, database.VoteRequestUnconfirmed, database.VoteRequestApproved)
+	if err != nil {
+		return fmt.Errorf("failed to update vote request %d: %v", id, err)
+	}
+	if !updated {
+		return fmt.Errorf("vote request %d is no longer unconfirmed", id)
+	}
+
+	if err := updateVoteButtons(ctx, callback, blockID, fmt.Sprintf(":ballot_box_with_check: <@%s> confirmed %s", userID, voting.DescribeVoteRequest(req))); err != nil {
+		log.Printf("failed to update confirmation of vote request %d: %v", id, err)
+	}
+
+	status, details := database.VoteRequestExecuted, voting.DescribeVoteRequest(req)
+	if err := voting.ExecVoteRequest(ctx, req, false, response); err != nil {
+		status, details = database.VoteRequestFailed, err.Error()
+	}
+
+	if _, err := db.UpdateVoteRequestStatus(id, database.VoteRequestApproved, status); err != nil {
+		log.Printf("failed to update vote request %d: %v", id, err)
+	}
+	voting.AuditVoteRequest(ctx, id, userID, status, details)
+
+	return nil
+}
+
+// rejectVoteRequest rejects a pending vote request and cancels its scheduled
+// vote. Approvers and the requester can reject a request.
+func rejectVoteRequest(ctx types.Context, id int64, userID, reason string, response slacker.ResponseWriter) error {
+	db := ctx.Database()
+
+	req, err := db.GetVoteRequest(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPending(ctx, req); err != nil {
+		return err
+	}
+
+	if userID != req.RequestedBy && !ctx.Config().Approval.IsApprover(userID) {
+		voting.AuditVoteRequest(ctx, id, userID, "rejection denied", "user is not an approver")
+		return fmt.Errorf("<@%s> is not allowed to reject votes", userID)
+	}
+
+	updated, err := db.UpdateVoteRequestStatus(id, database.VoteRequestPending, database.VoteRequestRejected)
+	if err != nil {
+		return fmt.Errorf("failed to update vote request %d: %v", id, err)
+	}
+	if !updated {
+		return fmt.Errorf("vote request %d is no longer pending", id)
+	}
+
+	voting.AuditVoteRequest(ctx, id, userID, "rejected", reason)
+	msg := fmt.Sprintf("Vote request *#%d* rejected by <@%s>", id, userID)
+	if reason != "" {
+		msg += ": " + reason
+	}
+
+	scheduled, found, err := db.GetScheduledVoteByRequest(id)
+	if err != nil {
+		log.Printf("failed to get scheduled vote of vote request %d: %v", id, err)
+	}
+	if found {
+		if _, err := db.UpdateScheduledVoteStatus(scheduled.ID, database.ScheduledVotePending, database.ScheduledVoteCancelled); err != nil {
+			log.Printf("failed to update scheduled vote %d: %v", scheduled.ID, err)
+		}
+		msg += fmt.Sprintf(", scheduled vote *#%d* is cancelled", scheduled.ID)
+	}
+	response.Reply(msg)
+	return nil
+}
+
+// checkPending returns an error if the request can no longer be approved or
+// rejected. A request whose timeout has passed is expired.
+func checkPending(ctx types.Context, req database.VoteRequest) error {
+	if req.Status != database.VoteRequestPending {
+		return fmt.Errorf("vote request %d is %s", req.ID, req.Status)
+	}
+
+	if time.Now().UTC().Unix() < req.ExpiresAt {
+		return nil
+	}
+
+	updated, err := ctx.Database().UpdateVoteRequestStatus(req.ID, database.VoteRequestPending, database.VoteRequestExpired)
+	if err != nil {
+		return fmt.Errorf("failed to update vote request %d: %v", req.ID, err)
+	}
+	if updated {
+		voting.AuditVoteRequest(ctx, req.ID, "", database.VoteRequestExpired, "approval timeout passed")
+	}
+
+	return fmt.Errorf("vote request %d is expired", req.ID)
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Flags of the vote commands, given as key=value. Values with spaces,
+// punctuation or line breaks are quoted, e.g. memo="Voting yes, see forum".
+const (
+	memoFlag      = "memo"
+	metadataFlag  = "metadata"
+	gasPricesFlag = "gas-prices"
+)
+
+// dryRunFlag can be added to the vote commands to only simulate the vote
+const dryRunFlag = "--dry-run"
+
+// commandArgs holds the arguments of a command parsed from the message text
+type commandArgs struct {
+	positional []string
+	flags      map[string]string
+	// params is the text following the command
+	params string
+	// dryRun is true if the unquoted dry run flag was given
+	dryRun bool
+}
+
+// parseCommandArgs splits the message text into the arguments following the
+// command, which is the first word after the mentions. Arguments are
+// separated by white space unless they are quoted, quotes may span several
+// lines and \" escapes a quote. Arguments of the form key=value are returned
+// as flags if key is one of the given flags. The unquoted dry run flag sets
+// dryRun and is not returned as an argument.
+func parseCommandArgs(text, command string, flags ...string) (commandArgs, error) {
+	tokens, err := splitArgs(text)
+	if err != nil {
+		return commandArgs{}, err
+	}
+
+	// the text of app mentions starts with the mention of the bot
+	start := 0
+	for start < len(tokens) && !tokens[start].quoted && strings.HasPrefix(tokens[start].value, "<@") {
+		start++
+	}
+
+	args := commandArgs{flags: make(map[string]string)}
+	if start == len(tokens) || tokens[start].quoted || !strings.EqualFold(tokens[start].value, command) {
+		return args, fmt.Errorf("command %s not found in %q", command, text)
+	}
+	args.params = strings.TrimSpace(html.UnescapeString(text)[tokens[start].end:])
+
+	for _, token := range tokens[start+1:] {
+		if !token.quoted && token.value == dryRunFlag {
+			args.dryRun = true
+			continue
+		}
+
+		if token.keyLen > 0 && isFlag(token.value[:token.keyLen], flags) {
+			args.flags[token.value[:token.keyLen]] = token.value[token.keyLen+1:]
+			continue
+		}
+
+		args.positional = append(args.positional, token.value)
+	}
+
+	return args, nil
+}
+
+// value returns the flag if it was given, otherwise the positional argument
+// at the index if there is one.
+func (a commandArgs) value(flag string, index int) string {
+	if value, ok := a.flags[flag]; ok {
+		return value
+	}
+
+	if index < len(a.positional) {
+		return a.positional[index]
+	}
+
+	return ""
+}
+
+type argToken struct {
+	value string
+	// quoted is true if any part of the value was quoted
+	quoted bool
+	// keyLen is the length of the key if the token starts with an unquoted
+	// key=, otherwise 0
+	keyLen int
+	// end is the offset following the token in the unescaped text
+	end int
+}
+
+// splitArgs splits the text on white space outside of quotes and removes the
+// quotes. Slack escapes &, < and > and may send typographic quotes, both are
+// undone.
+func splitArgs(text string) ([]argToken, error) {
+	var tokens []argToken
+	var current strings.Builder
+	var token argToken
+	inToken, inQuotes, escaped := false, false, false
+
+	unescaped := html.UnescapeString(text)
+	for i, r := range unescaped {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case inQuotes && r == '\\':
+			escaped = true
+		case isQuote(r):
+			inQuotes = !inQuotes
+			inToken = true
+			token.quoted = true
+		case !inQuotes && unicode.IsSpace(r):
+			if inToken {
+				token.value, token.end = current.String(), i
+				tokens = append(tokens, token)
+				current.Reset()
+				token = argToken{}
+				inToken = false
+			}
+		default:
+			if r == '=' && !token.quoted && token.keyLen == 0 {
+				token.keyLen = current.Len()
+			}
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in %q", text)
+	}
+
+	if inToken {
+		token.value, token.end = current.String(), len(unescaped)
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '“' || r == '”'
+}
+
+func isFlag(key string, flags []string) bool {
+	for _, flag := range flags {
+		if key == flag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []argToken
+		wantErr string
+	}{
+		{
+			name: "white space",
+			text: " vote  cosmoshub\t12\nyes ",
+			want: []argToken{{value: "vote", end: 5}, {value: "cosmoshub", end: 16}, {value: "12", end: 19}, {value: "yes", end: 23}},
+		},
+		{
+			name: "quoted value with spaces and punctuation",
+			text: `"Voting yes, see: forum!" no`,
+			want: []argToken{{value: "Voting yes, see: forum!", quoted: true, end: 25}, {value: "no", end: 28}},
+		},
+		{
+			name: "quote spanning lines",
+			text: "\"first line\nsecond line\"",
+			want: []argToken{{value: "first line\nsecond line", quoted: true, end: 24}},
+		},
+		{
+			name: "escaped quote and backslash",
+			text: `"say \"yes\" \\ no"`,
+			want: []argToken{{value: `say "yes" \ no`, quoted: true, end: 19}},
+		},
+		{
+			name: "backslash outside quotes",
+			text: `a\b`,
+			want: []argToken{{value: `a\b`, end: 3}},
+		},
+		{
+			name: "typographic quotes",
+			text: "memo=“Voting yes”",
+			want: []argToken{{value: "memo=Voting yes", quoted: true, keyLen: 4, end: 21}},
+		},
+		{
+			name: "key=value",
+			text: "gas-prices=0.25uatom",
+			want: []argToken{{value: "gas-prices=0.25uatom", keyLen: 10, end: 20}},
+		},
+		{
+			name: "quoted key=value",
+			text: `memo="a=b c"`,
+			want: []argToken{{value: "memo=a=b c", quoted: true, keyLen: 4, end: 12}},
+		},
+		{
+			name: "equals sign in quoted key",
+			text: `"memo=x" y`,
+			want: []argToken{{value: "memo=x", quoted: true, end: 8}, {value: "y", end: 10}},
+		},
+		{
+			name: "empty quotes",
+			text: `memo=""`,
+			want: []argToken{{value: "memo=", quoted: true, keyLen: 4, end: 7}},
+		},
+		{
+			name: "slack escapes",
+			text: "memo=&quot;a &amp; b&quot; &lt;@U1&gt;",
+			want: []argToken{{value: "memo=a & b", quoted: true, keyLen: 4, end: 12}, {value: "<@U1>", end: 18}},
+		},
+		{
+			name: "dry run flag",
+			text: "vote cosmoshub 12 yes --dry-run",
+			want: []argToken{{value: "vote", end: 4}, {value: "cosmoshub", end: 14}, {value: "12", end: 17}, {value: "yes", end: 21}, {value: "--dry-run", end: 31}},
+		},
+		{name: "empty", text: "  "},
+		{name: "unterminated quote", text: `memo="Voting yes`, wantErr: "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := splitArgs(tt.text)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tokens)
+		})
+	}
+}
+
+func TestParseCommandArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		command    string
+		positional []string
+		flags      map[string]string
+		params     string
+		dryRun     bool
+		wantErr    string
+	}{
+		{
+			name:       "positional",
+			text:       "vote cosmoshub 12 yes 0.25uatom",
+			command:    "vote",
+			positional: []string{"cosmoshub", "12", "yes", "0.25uatom"},
+			flags:      map[string]string{},
+			params:     "cosmoshub 12 yes 0.25uatom",
+		},
+		{
+			name:       "flags and dry run",
+			text:       `<@U012AB3CD> vote cosmoshub 12 yes --dry-run gas-prices=0.25uatom memo="vote yes, see the forum"`,
+			command:    "vote",
+			positional: []string{"cosmoshub", "12", "yes"},
+			flags:      map[string]string{gasPricesFlag: "0.25uatom", memoFlag: "vote yes, see the forum"},
+			params:     `cosmoshub 12 yes --dry-run gas-prices=0.25uatom memo="vote yes, see the forum"`,
+			dryRun:     true,
+		},
+		{
+			name:       "dry run flag in memo",
+			text:       `vote cosmoshub 12 yes memo="a --dry-run b"`,
+			command:    "vote",
+			positional: []string{"cosmoshub", "12", "yes"},
+			flags:      map[string]string{memoFlag: "a --dry-run b"},
+			params:     `cosmoshub 12 yes memo="a --dry-run b"`,
+		},
+		{
+			name:       "unknown key stays positional",
+			text:       "vote-weighted cosmoshub 12 yes=0.7,abstain=0.3",
+			command:    "vote-weighted",
+			positional: []string{"cosmoshub", "12", "yes=0.7,abstain=0.3"},
+			flags:      map[string]string{},
+			params:     "cosmoshub 12 yes=0.7,abstain=0.3",
+		},
+		{
+			name:       "quoted dry run is positional",
+			text:       `vote cosmoshub 12 yes 0.25uatom "--dry-run"`,
+			command:    "vote",
+			positional: []string{"cosmoshub", "12", "yes", "0.25uatom", "--dry-run"},
+			flags:      map[string]string{},
+			params:     `cosmoshub 12 yes 0.25uatom "--dry-run"`,
+		},
+		{
+			name:       "reject reason",
+			text:       "<@U012AB3CD> reject-vote 3 wrong vote option",
+			command:    "reject-vote",
+			positional: []string{"3", "wrong", "vote", "option"},
+			flags:      map[string]string{},
+			params:     "3 wrong vote option",
+		},
+		{
+			name:    "command is not the first word",
+			text:    "please vote cosmoshub 12 yes",
+			command: "vote",
+			wantErr: "command vote not found",
+		},
+		{
+			name:    "command only in memo",
+			text:    `vote-batch cosmoshub 12=yes memo="vote"`,
+			command: "vote",
+			wantErr: "command vote not found",
+		},
+		{
+			name:    "quoted command",
+			text:    `"vote" cosmoshub 12 yes`,
+			command: "vote",
+			wantErr: "command vote not found",
+		},
+		{
+			name:    "unterminated quote",
+			text:    `vote cosmoshub 12 yes memo="no end`,
+			command: "vote",
+			wantErr: "unterminated quote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := parseCommandArgs(tt.text, tt.command, memoFlag, metadataFlag, gasPricesFlag)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.positional, args.positional)
+			assert.Equal(t, tt.flags, args.flags)
+			assert.Equal(t, tt.params, args.params)
+			assert.Equal(t, tt.dryRun, args.dryRun)
+		})
+	}
+}
+
+func TestCommandArgsValue(t *testing.T) {
+	args := commandArgs{positional: []string{"cosmoshub", "12", "yes", "0.1uatom"}, flags: map[string]string{memoFlag: "from flag"}}
+	assert.Equal(t, "from flag", args.value(memoFlag, 0))
+	assert.Equal(t, "0.1uatom", args.value(gasPricesFlag, 3))
+	assert.Equal(t, "", args.value(metadataFlag, 4))
+}
+
+func TestCommandParams(t *testing.T) {
+	tests := []struct {
+		text    string
+		command string
+		want    string
+	}{
+		{"vote cosmoshub 12 yes", "vote", "cosmoshub 12 yes"},
+		{"<@U012AB3CD>  vote   cosmoshub 12 yes ", "vote", "cosmoshub 12 yes"},
+		{`vote cosmoshub 12 yes memo="vote &amp; see"`, "vote", `cosmoshub 12 yes memo="vote & see"`},
+		// the command within the params is not cut
+		{"reject-vote 3 wrong vote option", "reject-vote", "3 wrong vote option"},
+		{"list-commands", "list-commands", ""},
+		{"please vote cosmoshub 12 yes", "vote", "please vote cosmoshub 12 yes"},
+		{`vote cosmoshub 12 yes memo="open`, "vote", `vote cosmoshub 12 yes memo="open`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, commandParams(tt.text, tt.command))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// Filters of the audit-log command
+const (
+	fromFlag  = "from"
+	toFlag    = "to"
+	userFlag  = "user"
+	chainFlag = "chain"
+	limitFlag = "limit"
+)
+
+// addCommand registers the command and adds every run of it to the audit
+// log, with the result and the transactions reported by its handler. Denied
+// attempts are added as well. The command only runs for messages starting
+// with its name, see routeCommand.
+func addCommand(ctx types.Context, usage string, definition *slacker.CommandDefinition) {
+	command := strings.Fields(usage)[0]
+	newEntry := func(botCtx slacker.BotContext, request slacker.Request) database.AuditEntry {
+		event := botCtx.Event()
+		return database.AuditEntry{
+			UserID:    event.UserID,
+			Command:   command,
+			ChainName: auditChainName(ctx, request),
+			Params:    commandParams(event.Text, command),
+		}
+	}
+
+	if authorize := definition.AuthorizationFunc; authorize != nil {
+		definition.AuthorizationFunc = func(botCtx slacker.BotContext, request slacker.Request) bool {
+			if authorize(botCtx, request) {
+				return true
+			}
+
+			entry := newEntry(botCtx, request)
+			entry.Result = database.AuditResultDenied
+			voting.AddAuditEntry(ctx, entry)
+			return false
+		}
+	}
+
+	handler := definition.Handler
+	definition.Handler = func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+		entry := newEntry(botCtx, request)
+		writer := voting.NewAuditWriter(response)
+		handler(botCtx, request, writer)
+		voting.AddAuditEntry(ctx, writer.Entry(entry))
+	}
+
+	commands[command] = slacker.NewCommand(usage, definition)
+	ctx.Slacker().Command(usage, routedDefinition(definition))
+}
+
+// auditChainName returns the chain the command is run on, if it has one.
+// Validator addresses are resolved before the handler runs, as it may remove
+// the validator.
+func auditChainName(ctx types.Context, request slacker.Request) string {
+	for _, param := range []string{"chainName", "chainNameOptional"} {
+		if chainName := request.Param(param); chainName != "" {
+			return chainName
+		}
+	}
+
+	for _, param := range []string{"chainNameOrValidator", "validatorAddress"} {
+		target := request.Param(param)
+		if target == "" {
+			continue
+		}
+
+		if ctx.Database().HasValidator(target) {
+			if val, err := ctx.Database().GetValidator(target); err == nil {
+				return val.ChainName
+			}
+		}
+
+		if param == "chainNameOrValidator" {
+			return target
+		}
+	}
+
+	return ""
+}
+
+// commandParams returns the text following the command, or the text if it
+// does not start with the command
+func commandParams(text, command string) string {
+	args, err := parseCommandArgs(text, command)
+	if err != nil {
+		return text
+	}
+
+	return args.params
+}
+
+// formatAuditLog lists the audit entries as a table
+func formatAuditLog(entries []database.AuditEntry) string {
+	if len(entries) == 0 {
+		return "No audit entries found"
+	}
+
+	tableData := [][]string{{"Date", "User", "Command", "Chain", "Params", "Result", "Tx Hash"}}
+	for _, entry := range entries {
+		user := entry.UserID
+		if user == "" {
+			user = "bot"
+		}
+
+		tableData = append(tableData, []string{
+			time.Unix(entry.Date, 0).UTC().Format(time.RFC822),
+			user,
+			entry.Command,
+			entry.ChainName,
+			truncate(entry.Params, 60),
+			truncate(entry.Result, 60),
+			entry.TxHash,
+		})
+	}
+
+	return fmt.Sprintf("```%s```", formatTable(tableData))
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// requireRole returns the authorization func of a command which may only be
+// run by users with the role
+func requireRole(ctx types.Context, role config.Role) func(slacker.BotContext, slacker.Request) bool {
+	return func(botCtx slacker.BotContext, request slacker.Request) bool {
+		event := botCtx.Event()
+		return isAuthorized(ctx, event.UserID, role, event.Text)
+	}
+}
+
+// isAuthorized returns true if the user has the role. Denied attempts are
+// logged and announced in the channel of the bot.
+func isAuthorized(ctx types.Context, userID string, role config.Role, attempt string) bool {
+	authorization := ctx.Config().Authorization
+	if !authorization.Enabled() {
+		return true
+	}
+
+	userRole, err := getUserRole(ctx, userID)
+	if err != nil {
+		log.Printf("failed to get the role of %s: %v", userID, err)
+	}
+
+	if userRole.Allows(role) {
+		return true
+	}
+
+	log.Printf("denied %q of %s: requires the %s role, user has role %q", attempt, userID, role, userRole)
+
+	msg := fmt.Sprintf(":no_entry: <@%s> was denied `%s`, it requires the *%s* role", userID, attempt, role)
+	_, _, err = ctx.Slacker().APIClient().PostMessage(
+		ctx.Config().Slack.ChannelID,
+		slack.MsgOptionText(msg, false),
+	)
+	if err != nil {
+		log.Printf("failed to post denied command of %s: %v", userID, err)
+	}
+
+	return false
+}
+
+// requireApprover returns the authorization func of the approve and reject
+// commands. The approvers of the approval config may run them without a role,
+// other users need the voter role, e.g. to reject their own vote request.
+func requireApprover(ctx types.Context) func(slacker.BotContext, slacker.Request) bool {
+	return func(botCtx slacker.BotContext, request slacker.Request) bool {
+		event := botCtx.Event()
+		if ctx.Config().Approval.IsApprover(event.UserID) {
+			return true
+		}
+
+		return isAuthorized(ctx, event.UserID, config.RoleVoter, event.Text)
+	}
+}
+
+// getUserRole returns the most privileged role of the user, which is given
+// to the user directly or through a user group. An empty role is returned for
+// users without a role. The members of user groups are cached, see
+// groupMembersTTL.
+func getUserRole(ctx types.Context, userID string) (config.Role, error) {
+	var groupErr error
+	for _, roleMembers := range ctx.Config().Authorization.Members() {
+		for _, member := range roleMembers.Members {
+			if member == userID {
+				return roleMembers.Role, nil
+			}
+
+			if !strings.HasPrefix(member, "S") {
+				continue
+			}
+
+			users, err := userGroups.members(member, ctx.Slacker().APIClient().GetUserGroupMembers)
+			if err != nil {
+				groupErr = fmt.Errorf("failed to get the members of user group %s: %v", member, err)
+				continue
+			}
+
+			for _, user := range users {
+				if user == userID {
+					return roleMembers.Role, nil
+				}
+			}
+		}
+	}
+
+	return "", groupErr
+}
+
+// groupMembersTTL is how long the members of a user group are cached, so that
+// commands and the App Home don't query Slack on every run. Changes to a user
+// group apply once its members expire.
+const groupMembersTTL = 5 * time.Minute
+
+var userGroups = &groupCache{ttl: groupMembersTTL, groups: make(map[string]cachedGroup)}
+
+// groupCache holds the members of the user groups by group id
+type groupCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	groups map[string]cachedGroup
+}
+
+type cachedGroup struct {
+	users     []string
+	fetchedAt time.Time
+}
+
+// members returns the cached members of the user group, or fetches them if
+// they are missing or expired. The expired members are returned if they can
+// not be fetched.
+func (c *groupCache) members(groupID string, fetch func(string) ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	cached, found := c.groups[groupID]
+	c.mu.Unlock()
+	if found && time.Since(cached.fetchedAt) < c.ttl {
+		return cached.users, nil
+	}
+
+	users, err := fetch(groupID)
+	if err != nil {
+		if found {
+			log.Printf("failed to refresh the members of user group %s, using the cached members: %v", groupID, err)
+			return cached.users, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.groups[groupID] = cachedGroup{users: users, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return users, nil
+}
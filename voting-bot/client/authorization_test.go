@@ -0,0 +1,60 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupCache(t *testing.T) {
+	fetches := 0
+	members := []string{"U1", "U2"}
+	var fetchErr error
+	fetch := func(groupID string) ([]string, error) {
+		fetches++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return members, nil
+	}
+
+	cache := &groupCache{ttl: time.Hour, groups: make(map[string]cachedGroup)}
+
+	users, err := cache.members("S1", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U1", "U2"}, users)
+	assert.Equal(t, 1, fetches)
+
+	// cached within the ttl
+	members = []string{"U3"}
+	users, err = cache.members("S1", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U1", "U2"}, users)
+	assert.Equal(t, 1, fetches)
+
+	// groups are cached on their own
+	users, err = cache.members("S2", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U3"}, users)
+	assert.Equal(t, 2, fetches)
+
+	// expired members are fetched again
+	cache.groups["S1"] = cachedGroup{users: []string{"U1", "U2"}, fetchedAt: time.Now().Add(-2 * time.Hour)}
+	users, err = cache.members("S1", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U3"}, users)
+	assert.Equal(t, 3, fetches)
+
+	// expired members are used if they can not be fetched
+	fetchErr = errors.New("ratelimited")
+	cache.groups["S1"] = cachedGroup{users: []string{"U4"}, fetchedAt: time.Now().Add(-2 * time.Hour)}
+	users, err = cache.members("S1", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U4"}, users)
+	assert.Equal(t, 4, fetches)
+
+	_, err = cache.members("S3", fetch)
+	assert.EqualError(t, err, "ratelimited")
+}
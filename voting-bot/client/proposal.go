@@ -0,0 +1,134 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/vitwit/authz-apps/voting-bot/jobs"
+)
+
+// maxSummaryLength and maxMessageLength keep the proposal details within the
+// size of a Slack message
+const (
+	maxSummaryLength = 1500
+	maxMessageLength = 1000
+)
+
+// formatProposalDetails formats the proposal, its tally compared with the
+// quorum and thresholds of the chain and the votes of our validators
+func formatProposalDetails(d jobs.ProposalDetails) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%s proposal #%s*: %s\n", d.ChainName, d.ProposalID, d.Title)
+	fmt.Fprintf(&b, "*Status*: %s\n", d.Status)
+	if d.Proposer != "" {
+		fmt.Fprintf(&b, "*Proposer*: %s\n", d.Proposer)
+	}
+
+	if d.Summary != "" {
+		fmt.Fprintf(&b, "*Summary*:\n>%s\n", strings.ReplaceAll(truncate(d.Summary, maxSummaryLength), "\n", "\n>"))
+	}
+
+	if len(d.Messages) > 0 {
+		b.WriteString("*Messages*:\n")
+		for i, message := range d.Messages {
+			if message.Decoded {
+				fmt.Fprintf(&b, "%d. `%s`\n%s\n", i+1, message.Type, truncate(message.Summary, maxMessageLength))
+			} else {
+				fmt.Fprintf(&b, "%d. `%s`\n```%s```\n", i+1, message.Type, truncate(message.Summary, maxMessageLength))
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, "*Deposit*: %s\n", d.TotalDeposit)
+	fmt.Fprintf(&b, "*Submitted*: %s, *deposit end*: %s\n", formatTime(d.SubmitTime), formatTime(d.DepositEndTime))
+	fmt.Fprintf(&b, "*Voting*: %s to %s", formatTime(d.VotingStartTime), formatTime(d.VotingEndTime))
+	if endTime, err := time.Parse(time.RFC3339, d.VotingEndTime); err == nil && time.Until(endTime) > 0 {
+		fmt.Fprintf(&b, " (ends in %s)", jobs.FormatTimeLeft(time.Until(endTime)))
+	}
+	b.WriteString("\n")
+
+	total := d.Tally.Total()
+	fmt.Fprintf(&b, "*Tally*: yes %s, no %s, no with veto %s, abstain %s\n",
+		percent(d.Tally.Yes, total), percent(d.Tally.No, total), percent(d.Tally.NoWithVeto, total), percent(d.Tally.Abstain, total))
+
+	if d.BondedTokens.IsPositive() {
+		turnout := sdk.NewDecFromInt(total).QuoInt(d.BondedTokens)
+		fmt.Fprintf(&b, "*Turnout*: %s of bonded tokens, quorum %s %s\n",
+			formatDec(turnout), formatDec(d.TallyParams.Quorum), checkMark(turnout.GTE(d.TallyParams.Quorum)))
+	}
+
+	if nonAbstain := total.Sub(d.Tally.Abstain); nonAbstain.IsPositive() {
+		yes := sdk.NewDecFromInt(d.Tally.Yes).QuoInt(nonAbstain)
+		fmt.Fprintf(&b, "*Threshold*: %s yes of non-abstain votes, threshold %s %s\n",
+			formatDec(yes), formatDec(d.TallyParams.Threshold), checkMark(yes.GT(d.TallyParams.Threshold)))
+	}
+
+	if total.IsPositive() {
+		veto := sdk.NewDecFromInt(d.Tally.NoWithVeto).QuoInt(total)
+		fmt.Fprintf(&b, "*Veto*: %s, veto threshold %s %s\n",
+			formatDec(veto), formatDec(d.TallyParams.VetoThreshold), checkMark(veto.LT(d.TallyParams.VetoThreshold)))
+	}
+
+	var validators []string
+	for valAddr := range d.Votes {
+		validators = append(validators, valAddr)
+	}
+	sort.Strings(validators)
+
+	b.WriteString("*Our votes*:\n")
+	if len(validators) == 0 {
+		b.WriteString("no validator is registered for the chain\n")
+	}
+	for _, valAddr := range validators {
+		vote := d.Votes[valAddr]
+		if vote == "" {
+			vote = "not voted"
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", valAddr, vote)
+	}
+
+	return b.String()
+}
+
+func truncate(str string, length int) string {
+	runes := []rune(str)
+	if len(runes) <= length {
+		return str
+	}
+
+	return string(runes[:length]) + "…"
+}
+
+func percent(part, total sdk.Int) string {
+	if !total.IsPositive() {
+		return "0%"
+	}
+
+	return formatDec(sdk.NewDecFromInt(part).QuoInt(total))
+}
+
+// formatDec formats a ratio as a percentage
+func formatDec(dec sdk.Dec) string {
+	return fmt.Sprintf("%.2f%%", dec.MulInt64(100).MustFloat64())
+}
+
+func checkMark(ok bool) string {
+	if ok {
+		return ":white_check_mark:"
+	}
+
+	return ":x:"
+}
+
+func formatTime(str string) string {
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return str
+	}
+
+	return t.UTC().Format(time.RFC822)
+}
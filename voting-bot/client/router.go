@@ -0,0 +1,84 @@
+package client
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/shomali11/slacker"
+)
+
+// commands holds the registered commands by name. Slacker runs the first
+// command whose expression matches anywhere in the message, so "reject-vote 3
+// wrong vote option" would match vote. Every command is therefore routed to
+// the command named by the first word of the message.
+var commands = make(map[string]slacker.Command)
+
+// commandName returns the first word of the message, skipping mentions of the
+// bot
+func commandName(text string) string {
+	for _, field := range strings.Fields(html.UnescapeString(text)) {
+		if strings.HasPrefix(field, "<@") {
+			continue
+		}
+
+		return strings.ToLower(field)
+	}
+
+	return ""
+}
+
+// routeCommand returns the command named by the first word of the message
+func routeCommand(text string) (slacker.Command, bool) {
+	command, found := commands[commandName(text)]
+	return command, found
+}
+
+// routedDefinition returns a copy of the definition whose authorization and
+// handler run the command named by the first word of the message instead of
+// the command slacker matched.
+func routedDefinition(definition *slacker.CommandDefinition) *slacker.CommandDefinition {
+	routed := *definition
+	routed.AuthorizationFunc = func(botCtx slacker.BotContext, _ slacker.Request) bool {
+		command, request, found := routedRequest(botCtx)
+		if !found || request == nil || command.Definition().AuthorizationFunc == nil {
+			return true
+		}
+
+		return command.Definition().AuthorizationFunc(botCtx, request)
+	}
+
+	routed.Handler = func(botCtx slacker.BotContext, _ slacker.Request, response slacker.ResponseWriter) {
+		command, request, found := routedRequest(botCtx)
+		if !found {
+			response.Reply(fmt.Sprintf("Unknown command %s, see list-commands", commandName(botCtx.Event().Text)))
+			return
+		}
+
+		if request == nil {
+			response.Reply(fmt.Sprintf("Usage: `%s`", command.Usage()))
+			return
+		}
+
+		command.Execute(botCtx, request, response)
+	}
+
+	return &routed
+}
+
+// routedRequest returns the command named by the message and its request. The
+// request is nil if the message doesn't match the usage of the command.
+func routedRequest(botCtx slacker.BotContext) (slacker.Command, slacker.Request, bool) {
+	text := botCtx.Event().Text
+	command, found := routeCommand(text)
+	if !found {
+		return nil, nil, false
+	}
+
+	params, match := command.Match(text)
+	if !match {
+		return command, nil, true
+	}
+
+	return command, slacker.NewRequest(botCtx, params), true
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shomali11/slacker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// runCommand returns the name of the command that runs for the message, the
+// way slacker picks the first matching command which routes it on.
+func runCommand(t *testing.T, skr *slacker.Slacker, text string) string {
+	for _, command := range skr.BotCommands() {
+		if _, match := command.Match(text); !match {
+			continue
+		}
+
+		routed, found := routeCommand(text)
+		if !found {
+			return ""
+		}
+
+		_, match := routed.Match(text)
+		require.True(t, match, "%s doesn't match the usage of %s", text, routed.Usage())
+		return commandName(routed.Usage())
+	}
+
+	return ""
+}
+
+func TestCommandRouting(t *testing.T) {
+	skr := slacker.NewClient("xoxb-test", "xapp-test")
+	registerCommands(types.NewContext(zerolog.Nop(), nil, &config.Config{}, skr))
+
+	for _, command := range skr.BotCommands() {
+		name := commandName(command.Usage())
+		for _, example := range command.Definition().Examples {
+			assert.Equal(t, name, runCommand(t, skr, example), example)
+			assert.Equal(t, name, runCommand(t, skr, "<@U012AB3CD> "+example), example)
+		}
+	}
+
+	tests := []struct {
+		text    string
+		command string
+	}{
+		{"reject-vote 3 wrong vote option", "reject-vote"},
+		{"approve-vote 3", "approve-vote"},
+		{`vote-weighted cosmoshub 12 yes=0.7,abstain=0.3 0.25uatom memo="we vote yes"`, "vote-weighted"},
+		{`vote-batch cosmoshub 12=yes,13=no 0.25uatom memo="vote as discussed"`, "vote-batch"},
+		{`schedule-vote cosmoshub 12 yes end-6h 0.25uatom memo="vote before the end"`, "schedule-vote"},
+		{"cancel-scheduled-vote 4", "cancel-scheduled-vote"},
+		{`vote cosmoshub 12 yes 0.25uatom memo="not a vote-batch or schedule-vote"`, "vote"},
+		{`<@U012AB3CD> vote-weighted cosmoshub 12 yes=0.5,no=0.5 memo=“we vote split”`, "vote-weighted"},
+		{"please vote cosmoshub 12 yes 0.25uatom", ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.command, runCommand(t, skr, tc.text), tc.text)
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/jobs"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// beforeEndPrefix marks a schedule relative to the end of the voting period,
+// e.g. end-6h
+const beforeEndPrefix = "end-"
+
+// scheduleVote validates the vote and stores it to be executed at executeAt,
+// which is either an RFC3339 time or an offset before the end of the voting
+// period of the proposal. If approvals are required, a vote request is created
+// which must be approved before the scheduled time.
+func scheduleVote(ctx types.Context, vote database.ScheduledVote, executeAt string, response slacker.ResponseWriter) error {
+	req := database.VoteRequest{
+		Target:     vote.Target,
+		ProposalID: vote.ProposalID,
+		VoteType:   vote.VoteType,
+		VoteOption: vote.VoteOption,
+	}
+	if err := voting.ValidateVoteRequest(ctx, req); err != nil {
+		return err
+	}
+
+	chainName, _, _, err := voting.GetVoteTargets(ctx, vote.Target)
+	if err != nil {
+		return err
+	}
+
+	votingEndTime, err := jobs.GetProposalVotingEndTime(ctx, chainName, vote.ProposalID)
+	if err != nil {
+		return fmt.Errorf("failed to get voting end time of %s proposal %s: %v", chainName, vote.ProposalID, err)
+	}
+
+	var at time.Time
+	if strings.HasPrefix(executeAt, beforeEndPrefix) {
+		vote.BeforeEnd, err = time.ParseDuration(strings.TrimPrefix(executeAt, beforeEndPrefix))
+		if err != nil || vote.BeforeEnd <= 0 {
+			return fmt.Errorf("invalid offset %s, expected e.g. %s6h", executeAt, beforeEndPrefix)
+		}
+
+		at = votingEndTime.Add(-vote.BeforeEnd)
+	} else {
+		at, err = time.Parse(time.RFC3339, executeAt)
+		if err != nil {
+			return fmt.Errorf("invalid time %s, expected e.g. 2024-05-01T12:00:00Z or %s6h", executeAt, beforeEndPrefix)
+		}
+	}
+
+	if at.Before(time.Now()) {
+		return fmt.Errorf("scheduled time %s has already passed", at.UTC().Format(time.RFC822))
+	}
+
+	if !at.Before(votingEndTime) {
+		return fmt.Errorf("scheduled time %s is after the end of the voting period at %s",
+			at.UTC().Format(time.RFC822), votingEndTime.UTC().Format(time.RFC822))
+	}
+
+	vote.ExecuteAt = at.Unix()
+	approval := ctx.Config().Approval
+	if approval.RequiredApprovals > 0 {
+		// the vote must be approved before it is executed
+		req.GasPrices, req.Memo, req.Metadata, req.RequestedBy = vote.GasPrices, vote.Memo, vote.Metadata, vote.ScheduledBy
+		vote.VoteRequestID, err = ctx.Database().AddVoteRequest(req, time.Until(at))
+		if err != nil {
+			return fmt.Errorf("failed to store vote request: %v", err)
+		}
+	}
+
+	id, err := ctx.Database().AddScheduledVote(vote)
+	if err != nil {
+		if vote.VoteRequestID != 0 {
+			if _, err := ctx.Database().UpdateVoteRequestStatus(vote.VoteRequestID, database.VoteRequestPending, database.VoteRequestCancelled); err != nil {
+				log.Printf("failed to update vote request %d: %v", vote.VoteRequestID, err)
+			}
+		}
+		return fmt.Errorf("failed to store scheduled vote: %v", err)
+	}
+
+	msg := fmt.Sprintf("Scheduled vote *#%d*: %s %s on %s proposal %s at %s, the voting period ends at %s. Cancel it with `cancel-scheduled-vote %d`.",
+		id, vote.VoteType, vote.VoteOption, vote.Target, vote.ProposalID, at.UTC().Format(time.RFC822), votingEndTime.UTC().Format(time.RFC822), id)
+	if vote.VoteRequestID != 0 {
+		voting.AuditVoteRequest(ctx, vote.VoteRequestID, vote.ScheduledBy, "requested", fmt.Sprintf("scheduled vote #%d: %s", id, voting.DescribeVoteRequest(req)))
+		msg += fmt.Sprintf("\nVote request *#%d*: %d approval(s) are required before the scheduled time, approve with `approve-vote %d` or reject with `reject-vote %d`.",
+			vote.VoteRequestID, approval.RequiredApprovals, vote.VoteRequestID, vote.VoteRequestID)
+	}
+
+	return response.Reply(msg)
+}
@@ -2,14 +2,17 @@ package client
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shomali11/slacker"
 	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/database"
 	"github.com/vitwit/authz-apps/voting-bot/jobs"
 	"github.com/vitwit/authz-apps/voting-bot/keyring"
 	"github.com/vitwit/authz-apps/voting-bot/types"
@@ -20,10 +23,33 @@ import (
 // Creates and initialises commands
 func InitializeBotcommands(ctx types.Context) error {
 	skr := ctx.Slacker()
+	registerCommands(ctx)
+
+	skr.Interactive(func(botCtx slacker.InteractiveBotContext, callback *slack.InteractionCallback) {
+		handleInteraction(ctx, botCtx, callback)
+	})
+
+	skr.DefaultInnerEvent(func(_ context.Context, evt interface{}, _ *socketmode.Request) {
+		handleInnerEvent(ctx, evt)
+	})
+
+	ctx1, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := skr.Listen(ctx1)
+	if err != nil {
+		return fmt.Errorf("%s", err)
+	}
+	return err
+}
+
+// registerCommands adds the bot commands to slacker
+func registerCommands(ctx types.Context) {
 	// Command to register validator address with chain name
-	skr.Command("register-validator <chainName> <validatorAddress>", &slacker.CommandDefinition{
-		Description: "registers a new validator",
-		Examples:    []string{"register-validator cosmoshub cosmos1a..."},
+	addCommand(ctx, "register-validator <chainName> <validatorAddress>", &slacker.CommandDefinition{
+		Description:       "registers a new validator",
+		Examples:          []string{"register-validator cosmoshub cosmos1a..."},
+		AuthorizationFunc: requireRole(ctx, config.RoleAdmin),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			chainName := request.Param("chainName")
 			validatorAddress := request.Param("validatorAddress")
@@ -55,9 +81,10 @@ func InitializeBotcommands(ctx types.Context) error {
 	})
 
 	// Command to remove validator address from db
-	skr.Command("remove-validator <validatorAddress>", &slacker.CommandDefinition{
-		Description: "remove an existing validator",
-		Examples:    []string{"remove-validator cosmos1a..."},
+	addCommand(ctx, "remove-validator <validatorAddress>", &slacker.CommandDefinition{
+		Description:       "remove an existing validator",
+		Examples:          []string{"remove-validator cosmos1a..."},
+		AuthorizationFunc: requireRole(ctx, config.RoleAdmin),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			validatorAddress := request.Param("validatorAddress")
 			if !ctx.Database().HasValidator(validatorAddress) {
@@ -71,7 +98,7 @@ func InitializeBotcommands(ctx types.Context) error {
 	})
 
 	// Creates keys which are used for voting
-	skr.Command("create-key <chainName> <keyType> <keyNameOptional>", &slacker.CommandDefinition{
+	addCommand(ctx, "create-key <chainName> <keyType> <keyNameOptional>", &slacker.CommandDefinition{
 		Description: `create a new account with key name.\n
 		Keys need to be funded manually and given authorization to vote in order to use them while voting.\n
 		The granter must give the vote authorization to the grantee key before the voting can proceed.\n
@@ -79,10 +106,12 @@ func InitializeBotcommands(ctx types.Context) error {
 		For Cosmos chain:\n
 		Usage: simd tx authz grant <grantee> <authorization_type> --msg-type <msg_type> --from <granter> [flags]\n
 		Example: simd tx authz grant cosmos1... --msg-type /cosmos.gov.v1beta1.MsgVote --from granter\n
+		On chains with gov v1, grant /cosmos.gov.v1.MsgVote instead to include vote metadata.\n
 		The authorized keys can then be funded to have the ability to vote on behalf of the granter.\n
 		The following command can be used to fund the key:\n
 		simd tx bank send [from_key_or_address] [to_address] [amount] [flags]`,
-		Examples: []string{"create-key cosmoshub voting myKey"},
+		Examples:          []string{"create-key cosmoshub voting myKey"},
+		AuthorizationFunc: requireRole(ctx, config.RoleAdmin),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			keyName := request.StringParam("keyNameOptional", "")
 			chainName := request.Param("chainName")
@@ -110,6 +139,7 @@ func InitializeBotcommands(ctx types.Context) error {
 					" *NOTE*\n *This key cannot be used in voting until it has the vote authorization from granter and got funded. "+
 					"The vote authorization can be given using the following command:*\n "+
 					"```simd tx authz grant <grantee> <authorization_type=generic> --msg-type /cosmos.gov.v1beta1.MsgVote  --from <granter> [flags]```\n"+
+					"*On chains with gov v1, grant* `/cosmos.gov.v1.MsgVote` *instead to include vote metadata.*\n"+
 					"\n *The authorized keys can be funded using the following command:* \n "+
 					"```simd tx bank send [from_key_or_address] [to_address] [amount] [flags]```\n", keyName))
 			}
@@ -117,88 +147,431 @@ func InitializeBotcommands(ctx types.Context) error {
 	})
 
 	// Command to list all the commands present
-	skr.Command("list-commands", &slacker.CommandDefinition{
-		Description: "Lists all commands",
-		Examples:    []string{"list-commands"},
+	addCommand(ctx, "list-commands", &slacker.CommandDefinition{
+		Description:       "Lists all commands",
+		Examples:          []string{"list-commands"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
-			r := " *SLACK BOT COMMANDS* \n\n *• register-validator*: registers the validator using chain name and validator address\n```Command : register-validator <chainName> <validatorAddress>```\n *• remove-validator* : removes an existing validator data using validator address\n```Command:remove-validator <validatorAddress>```\n *• list-keys* : Lists all keys\n```Command:list-keys```\n *• list-proposals* : Lists all Active unvoted proposals \n```Command:list-proposals```\n *• list-validators* : List of all registered validators addresses with associated chains\n```Command:list-validators```\n* • vote* : votes on a proposal\n```Command:vote <chainName> <proposalId> <voteOption> <gasPrices> <memoOptional> <metadataOptional>\n```\n* • votes-history* : Lists history of all votes for a given chain\n```Command:votes-history <chainName> <startDate> <endDateOptional>\n```\n *• create-key* : Create a new account with key name. This key name is used while voting\n The key-type must be either voting or rewards.```Command:create-key <chainName> <chainName> <keyType> <keyNameOptional>```\n"
+			r := " *SLACK BOT COMMANDS* \n\n *• register-validator*: registers the validator using chain name and validator address\n```Command : register-validator <chainName> <validatorAddress>```\n *• remove-validator* : removes an existing validator data using validator address\n```Command:remove-validator <validatorAddress>```\n *• list-keys* : Lists all keys\n```Command:list-keys```\n *• list-proposals* : Lists all Active unvoted proposals \n```Command:list-proposals```\n *• upgrades* : lists upcoming software upgrades of our chains with their height and estimated time\n```Command:upgrades <chainNameOptional>```\n *• vote-stats* : shows how often our votes matched the final outcome of proposals and how many proposals were missed, per chain and validator\n```Command:vote-stats <chainNameOptional>```\n *• proposal* : shows a proposal with its decoded messages, tally against quorum and thresholds, deposit, voting dates and the votes of our validators\n```Command:proposal <chainName> <proposalId>```\n *• list-validators* : List of all registered validators addresses with associated chains\n```Command:list-validators```\n* • vote* : votes on a proposal\n```Command:vote <chainNameOrValidator> <proposalId> <voteOption> <gasPrices> <memoOptional> <metadataOptional>\n```\n Gas prices, memo and metadata can be given as flags, quoted values may contain spaces, punctuation and line breaks: `gas-prices=0.25uatom memo=\"...\" metadata=\"...\"`\n A chain name votes for all validators of the chain, a validator address only for that validator.\n Every vote is simulated first and broadcasted once you click Confirm, add `--dry-run` to only report the estimated gas and fee.\n* • vote-weighted* : splits the vote on a proposal across options, weights must add up to 1\n```Command:vote-weighted <chainNameOrValidator> <proposalId> <option=weight,...> <gasPrices> <memoOptional> <metadataOptional>\n```\n* • vote-batch* : votes on several proposals of a chain in a single transaction\n```Command:vote-batch <chainNameOrValidator> <proposalId=option,...> <gasPrices> <memoOptional>\n```\n* • schedule-vote* : queues a vote, executeAt is a time like 2024-05-01T12:00:00Z or end-6h for 6 hours before the end of the voting period. If approvals are configured, the vote must be approved before that time\n```Command:schedule-vote <chainNameOrValidator> <proposalId> <voteOption> <executeAt> <gasPrices> <memoOptional> <metadataOptional>```\n* • list-scheduled-votes* : lists pending scheduled votes\n```Command:list-scheduled-votes```\n* • cancel-scheduled-vote* : cancels a pending scheduled vote\n```Command:cancel-scheduled-vote <scheduledVoteId>```\n If approvals are configured, votes are stored as vote requests and only broadcasted once approved.\n* • approve-vote* : approves a pending vote request\n```Command:approve-vote <requestId>```\n* • reject-vote* : rejects a pending vote request\n```Command:reject-vote <requestId> <reasonOptional>```\n* • list-vote-requests* : lists pending vote requests\n```Command:list-vote-requests```\n* • vote-request-history* : shows the audit trail of a vote request\n```Command:vote-request-history <requestId>```\n* • rule-decisions* : lists the decisions of the voting rules on a chain\n```Command:rule-decisions <chainName>```\n* • votes-history* : Lists history of all votes for a given chain\n```Command:votes-history <chainName> <startDate> <endDateOptional>\n```\n *• audit-log* : lists who ran which command and what the jobs did, the latest first. Filters are optional: from and to are dates like 2024-05-01 or RFC3339 times, user is a Slack user, limit defaults to 50\n```Command:audit-log from=<date> to=<date> user=<@user> chain=<chainName> limit=<n>```\n *• create-key* : Create a new account with key name. This key name is used while voting\n The key-type must be either voting or rewards.```Command:create-key <chainName> <chainName> <keyType> <keyNameOptional>```\n If roles are configured, register-validator, remove-validator and create-key require the admin role, the vote, schedule and approval commands the voter role and all other commands the viewer role. The configured approvers may approve and reject votes without a role.\n"
 			response.Reply(r)
 		},
 	})
 
 	// Vote command is used to vote on the proposals based on proposal Id with vote option using key stored from db.
-	skr.Command(
-		"vote <chainName> <proposalId> <voteOption> <gasPrices> <memoOptional> <metadataOptional>",
+	// Given a chain name it votes for all validators registered on the chain, given a validator address only for that validator.
+	addCommand(
+		ctx,
+		"vote <chainNameOrValidator> <proposalId> <voteOption> <gasPrices> <memoOptional> <metadataOptional>",
 		&slacker.CommandDefinition{
-			Description: "votes on the proposal",
-			Examples:    []string{"vote cosmoshub 12 YES 0.25uatom example_memo example_metadata"},
+			Description:       "votes on the proposal for all validators of the chain or for a single validator",
+			Examples:          []string{`vote cosmoshub 12 YES gas-prices=0.25uatom memo="Voting yes, see the forum" metadata="ipfs://..."`, "vote cosmosvaloper1a... 12 YES 0.25uatom", "vote cosmoshub 12 YES 0.25uatom --dry-run"},
+			AuthorizationFunc: requireRole(ctx, config.RoleVoter),
 			Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
-				chainName := request.Param("chainName")
-
-				db := ctx.Database()
-				address, err := db.GetChainValidator(chainName)
+				args, err := parseVoteArgs(botCtx, "vote", 3)
 				if err != nil {
-					response.ReportError(fmt.Errorf("failed to get validator address from the database: %v", err))
+					response.ReportError(err)
 					return
 				}
 
-				chainInfo, err := voting.GetChainInfo(ctx, chainName)
-				if err != nil {
-					response.ReportError(fmt.Errorf("failed to get chain-info: %v", err))
-					return
+				req := database.VoteRequest{
+					Target:      args.positional[0],
+					ProposalID:  args.positional[1],
+					VoteType:    database.VoteTypeSingle,
+					VoteOption:  args.positional[2],
+					GasPrices:   args.value(gasPricesFlag, 3),
+					Memo:        args.value(memoFlag, 4),
+					Metadata:    args.value(metadataFlag, 5),
+					RequestedBy: botCtx.Event().UserID,
 				}
+				voting.SubmitVote(ctx, req, args.dryRun, response)
+			},
+		},
+	)
 
-				done := utils.SetBech32Prefixes(chainInfo)
-				hexAddr, err := utils.ValAddressFromBech32(address)
+	// Weighted vote command splits the validator vote across several options.
+	addCommand(
+		ctx,
+		"vote-weighted <chainNameOrValidator> <proposalId> <weightedOptions> <gasPrices> <memoOptional> <metadataOptional>",
+		&slacker.CommandDefinition{
+			Description:       "splits the vote on the proposal across options, weights must add up to 1",
+			Examples:          []string{`vote-weighted cosmoshub 12 yes=0.7,abstain=0.3 gas-prices=0.25uatom memo="Split vote" metadata="ipfs://..."`, "vote-weighted cosmoshub 12 yes=0.7,abstain=0.3 0.25uatom --dry-run"},
+			AuthorizationFunc: requireRole(ctx, config.RoleVoter),
+			Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+				args, err := parseVoteArgs(botCtx, "vote-weighted", 3)
 				if err != nil {
-					done()
-					response.ReportError(fmt.Errorf("error while getting validator address of chain %s", chainName))
+					response.ReportError(err)
 					return
 				}
 
-				granter, err := utils.AccAddressFromHexUnsafe(hex.EncodeToString(hexAddr.Bytes()))
-				done()
+				req := database.VoteRequest{
+					Target:      args.positional[0],
+					ProposalID:  args.positional[1],
+					VoteType:    database.VoteTypeWeighted,
+					VoteOption:  args.positional[2],
+					GasPrices:   args.value(gasPricesFlag, 3),
+					Memo:        args.value(memoFlag, 4),
+					Metadata:    args.value(metadataFlag, 5),
+					RequestedBy: botCtx.Event().UserID,
+				}
+				voting.SubmitVote(ctx, req, args.dryRun, response)
+			},
+		},
+	)
 
+	// Batch vote command votes on several proposals of a chain in a single transaction.
+	addCommand(
+		ctx,
+		"vote-batch <chainNameOrValidator> <votes> <gasPrices> <memoOptional>",
+		&slacker.CommandDefinition{
+			Description:       "votes on several proposals in a single transaction",
+			Examples:          []string{`vote-batch cosmoshub 12=yes,13=no,14=abstain gas-prices=0.25uatom memo="Batch vote"`, "vote-batch cosmoshub 12=yes,13=no 0.25uatom --dry-run"},
+			AuthorizationFunc: requireRole(ctx, config.RoleVoter),
+			Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+				args, err := parseVoteArgs(botCtx, "vote-batch", 2)
 				if err != nil {
-					response.ReportError(fmt.Errorf("error while decoding validator address %s", chainName))
+					response.ReportError(err)
 					return
 				}
 
-				voteOption := request.Param("voteOption")
-				fromKey, err := db.GetChainKey(chainName, "voting")
+				votes, err := voting.ParseBatchVotes(args.positional[1])
 				if err != nil {
-					response.ReportError(fmt.Errorf("error while getting key address of chain %s", chainName))
+					response.ReportError(err)
 					return
 				}
 
-				metadata := request.StringParam("metadataOptional", "")
-				memo := request.StringParam("memoOptional", "")
-				gasPrices := request.StringParam("gasPrices", "")
-				if len(memo) > 1 {
-					memo = strings.Replace(memo, "_", " ", -1)
-				}
-
-				if len(metadata) > 1 {
-					metadata = strings.Replace(metadata, "_", " ", -1)
+				req := database.VoteRequest{
+					Target:      args.positional[0],
+					ProposalID:  voting.BatchProposalIDs(votes),
+					VoteType:    database.VoteTypeBatch,
+					VoteOption:  args.positional[1],
+					GasPrices:   args.value(gasPricesFlag, 2),
+					Memo:        args.value(memoFlag, 3),
+					RequestedBy: botCtx.Event().UserID,
 				}
+				voting.SubmitVote(ctx, req, args.dryRun, response)
+			},
+		},
+	)
 
-				proposalID := request.Param("proposalId")
-				result, err := voting.ExecVote(ctx, chainName, proposalID, granter.String(), voteOption, fromKey, metadata, memo, gasPrices, response)
+	// Queues a vote to be executed at a given time or some hours before the end of the voting period.
+	addCommand(
+		ctx,
+		"schedule-vote <chainNameOrValidator> <proposalId> <voteOption> <executeAt> <gasPrices> <memoOptional> <metadataOptional>",
+		&slacker.CommandDefinition{
+			Description:       "schedules a vote at a given time or before the end of the voting period",
+			Examples:          []string{"schedule-vote cosmoshub 12 YES end-6h 0.25uatom", `schedule-vote cosmoshub 12 yes=0.7,abstain=0.3 2024-05-01T12:00:00Z gas-prices=0.25uatom memo="Split vote"`},
+			AuthorizationFunc: requireRole(ctx, config.RoleVoter),
+			Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+				args, err := parseVoteArgs(botCtx, "schedule-vote", 4)
 				if err != nil {
-					log.Printf("error on executing vote: %v", err)
-					response.ReportError(fmt.Errorf("error on executing vote: %v", err))
+					response.ReportError(err)
 					return
 				}
 
-				response.Reply(result)
+				vote := database.ScheduledVote{
+					Target:      args.positional[0],
+					ProposalID:  args.positional[1],
+					VoteType:    database.VoteTypeSingle,
+					VoteOption:  args.positional[2],
+					GasPrices:   args.value(gasPricesFlag, 4),
+					Memo:        args.value(memoFlag, 5),
+					Metadata:    args.value(metadataFlag, 6),
+					ScheduledBy: botCtx.Event().UserID,
+				}
+				if strings.Contains(vote.VoteOption, "=") {
+					vote.VoteType = database.VoteTypeWeighted
+				}
+
+				if err := scheduleVote(ctx, vote, args.positional[3], response); err != nil {
+					response.ReportError(err)
+				}
 			},
 		},
 	)
 
+	// Lists the scheduled votes which are not executed yet
+	addCommand(ctx, "list-scheduled-votes", &slacker.CommandDefinition{
+		Description:       "lists pending scheduled votes",
+		Examples:          []string{"list-scheduled-votes"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			votes, err := ctx.Database().GetPendingScheduledVotes()
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			if len(votes) == 0 {
+				response.Reply("There are no pending scheduled votes")
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"ID", "Target", "Proposal", "Vote", "Scheduled by", "Executes at"})
+			for _, vote := range votes {
+				executeAt := time.Unix(vote.ExecuteAt, 0).UTC().Format(time.RFC822)
+				if vote.BeforeEnd > 0 {
+					executeAt = fmt.Sprintf("%s (%s before end)", executeAt, vote.BeforeEnd)
+				}
+
+				tableData = append(tableData, []string{
+					strconv.FormatInt(vote.ID, 10), vote.Target, vote.ProposalID, fmt.Sprintf("%s %s", vote.VoteType, vote.VoteOption),
+					vote.ScheduledBy, executeAt,
+				})
+			}
+
+			response.Reply(fmt.Sprintf("```%s```", formatTable(tableData)))
+		},
+	})
+
+	// Cancels a scheduled vote which is not executed yet
+	addCommand(ctx, "cancel-scheduled-vote <scheduledVoteId>", &slacker.CommandDefinition{
+		Description:       "cancels a pending scheduled vote",
+		Examples:          []string{"cancel-scheduled-vote 4"},
+		AuthorizationFunc: requireRole(ctx, config.RoleVoter),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			id, err := strconv.ParseInt(request.Param("scheduledVoteId"), 10, 64)
+			if err != nil {
+				response.ReportError(fmt.Errorf("invalid scheduled vote id: %s", request.Param("scheduledVoteId")))
+				return
+			}
+
+			updated, err := ctx.Database().UpdateScheduledVoteStatus(id, database.ScheduledVotePending, database.ScheduledVoteCancelled)
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+			if !updated {
+				response.ReportError(fmt.Errorf("scheduled vote %d is not pending", id))
+				return
+			}
+
+			// the vote request of the scheduled vote can no longer be approved
+			if vote, err := ctx.Database().GetScheduledVote(id); err == nil && vote.VoteRequestID != 0 {
+				for _, status := range []string{database.VoteRequestPending, database.VoteRequestApproved} {
+					if _, err := ctx.Database().UpdateVoteRequestStatus(vote.VoteRequestID, status, database.VoteRequestCancelled); err != nil {
+						log.Printf("failed to update vote request %d: %v", vote.VoteRequestID, err)
+					}
+				}
+				voting.AuditVoteRequest(ctx, vote.VoteRequestID, botCtx.Event().UserID, database.VoteRequestCancelled, fmt.Sprintf("scheduled vote #%d cancelled", id))
+			}
+
+			log.Printf("scheduled vote %d cancelled by %s", id, botCtx.Event().UserID)
+			response.Reply(fmt.Sprintf("Scheduled vote *#%d* cancelled by <@%s>", id, botCtx.Event().UserID))
+		},
+	})
+
+	// Approves a pending vote request, the vote is broadcasted once it has the required approvals.
+	addCommand(ctx, "approve-vote <requestId>", &slacker.CommandDefinition{
+		Description:       "approves a pending vote request",
+		Examples:          []string{"approve-vote 3"},
+		AuthorizationFunc: requireApprover(ctx),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			id, err := strconv.ParseInt(request.Param("requestId"), 10, 64)
+			if err != nil {
+				response.ReportError(fmt.Errorf("invalid vote request id: %s", request.Param("requestId")))
+				return
+			}
+
+			if err := approveVoteRequest(ctx, id, botCtx.Event().UserID, response); err != nil {
+				response.ReportError(err)
+			}
+		},
+	})
+
+	// Rejects a pending vote request
+	addCommand(ctx, "reject-vote <requestId> <reasonOptional>", &slacker.CommandDefinition{
+		Description:       "rejects a pending vote request",
+		Examples:          []string{"reject-vote 3 wrong vote option"},
+		AuthorizationFunc: requireApprover(ctx),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			id, err := strconv.ParseInt(request.Param("requestId"), 10, 64)
+			if err != nil {
+				response.ReportError(fmt.Errorf("invalid vote request id: %s", request.Param("requestId")))
+				return
+			}
+
+			// the reason may have several words, slacker binds only the first
+			args, err := parseCommandArgs(botCtx.Event().Text, "reject-vote")
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			reason := ""
+			if len(args.positional) > 1 {
+				reason = strings.Join(args.positional[1:], " ")
+			}
+			if err := rejectVoteRequest(ctx, id, botCtx.Event().UserID, reason, response); err != nil {
+				response.ReportError(err)
+			}
+		},
+	})
+
+	// Lists the vote requests waiting for approvals
+	addCommand(ctx, "list-vote-requests", &slacker.CommandDefinition{
+		Description:       "lists pending vote requests",
+		Examples:          []string{"list-vote-requests"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			requests, err := ctx.Database().GetPendingVoteRequests()
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			if len(requests) == 0 {
+				response.Reply("There are no pending vote requests")
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"ID", "Target", "Proposal", "Vote", "Requested by", "Approvals", "Expires"})
+			for _, req := range requests {
+				tableData = append(tableData, []string{
+					strconv.FormatInt(req.ID, 10), req.Target, req.ProposalID, fmt.Sprintf("%s %s", req.VoteType, req.VoteOption),
+					req.RequestedBy, fmt.Sprintf("%d/%d", req.Approvals, ctx.Config().Approval.RequiredApprovals),
+					time.Unix(req.ExpiresAt, 0).UTC().Format(time.RFC822),
+				})
+			}
+
+			response.Reply(fmt.Sprintf("```%s```", formatTable(tableData)))
+		},
+	})
+
+	// Shows the audit trail of a vote request
+	addCommand(ctx, "vote-request-history <requestId>", &slacker.CommandDefinition{
+		Description:       "shows the audit trail of a vote request",
+		Examples:          []string{"vote-request-history 3"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			id, err := strconv.ParseInt(request.Param("requestId"), 10, 64)
+			if err != nil {
+				response.ReportError(fmt.Errorf("invalid vote request id: %s", request.Param("requestId")))
+				return
+			}
+
+			req, err := ctx.Database().GetVoteRequest(id)
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			entries, err := ctx.Database().GetVoteAudit(id)
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"Date", "User", "Action", "Details"})
+			for _, entry := range entries {
+				tableData = append(tableData, []string{
+					time.Unix(entry.Date, 0).UTC().Format(time.RFC822), entry.UserID, entry.Action, entry.Details,
+				})
+			}
+
+			response.Reply(fmt.Sprintf("Vote request *#%d* (%s): %s\n```%s```", req.ID, req.Status, voting.DescribeVoteRequest(req), formatTable(tableData)))
+		},
+	})
+
+	// Lists the decisions taken by the voting rules on a chain
+	addCommand(ctx, "rule-decisions <chainName>", &slacker.CommandDefinition{
+		Description:       "lists the decisions of the voting rules on a chain",
+		Examples:          []string{"rule-decisions cosmoshub"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			decisions, err := ctx.Database().GetRuleDecisions(request.Param("chainName"))
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			if len(decisions) == 0 {
+				response.Reply("No voting rule has matched a proposal on this chain")
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"Date", "Validator", "Proposal", "Rule", "Vote", "Action", "Details"})
+			for _, decision := range decisions {
+				tableData = append(tableData, []string{
+					time.Unix(decision.Date, 0).UTC().Format(time.RFC822), decision.ValidatorAddress, decision.ProposalID,
+					decision.RuleName, decision.VoteOption, decision.Action, decision.Details,
+				})
+			}
+
+			response.Reply(fmt.Sprintf("```%s```", formatTable(tableData)))
+		},
+	})
+
+	// Shows how often the votes of our validators matched the outcome of the
+	// proposals and how many proposals they missed
+	addCommand(ctx, "vote-stats <chainNameOptional>", &slacker.CommandDefinition{
+		Description:       "shows the alignment of our votes with proposal outcomes and the missed proposals per chain",
+		Examples:          []string{"vote-stats", "vote-stats cosmoshub"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			stats, err := ctx.Database().GetAlignmentStats(request.StringParam("chainNameOptional", ""))
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			if len(stats) == 0 {
+				response.Reply("No outcome of a proposal we were eligible to vote on is recorded yet")
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"Chain", "Validator", "Proposals", "Voted", "Aligned", "Abstained", "Missed", "Alignment"})
+			for _, s := range stats {
+				tableData = append(tableData, []string{
+					s.ChainName, s.ValidatorAddress, strconv.Itoa(s.Proposals), strconv.Itoa(s.Voted), strconv.Itoa(s.Aligned),
+					strconv.Itoa(s.Abstained), strconv.Itoa(s.Missed), fmt.Sprintf("%.1f%%", s.Alignment*100),
+				})
+			}
+
+			response.Reply(fmt.Sprintf("```%s```", formatTable(tableData)))
+		},
+	})
+
+	// Lists the software upgrades planned on the chains of our validators
+	addCommand(ctx, "upgrades <chainNameOptional>", &slacker.CommandDefinition{
+		Description:       "lists upcoming software upgrades with their height and estimated time",
+		Examples:          []string{"upgrades", "upgrades cosmoshub"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			upgrades, err := ctx.Database().GetUpgrades(request.StringParam("chainNameOptional", ""), database.UpgradeUpcoming)
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			if len(upgrades) == 0 {
+				response.Reply("No upcoming software upgrade")
+				return
+			}
+
+			var tableData [][]string
+			tableData = append(tableData, []string{"Chain", "Upgrade", "Height", "Proposal", "Estimated time", "In"})
+			for _, upgrade := range upgrades {
+				eta := time.Unix(upgrade.EstimatedTime, 0).UTC()
+				tableData = append(tableData, []string{
+					upgrade.ChainName, upgrade.Name, strconv.FormatInt(upgrade.Height, 10), upgrade.ProposalID,
+					eta.Format(time.RFC822), jobs.FormatTimeLeft(time.Until(eta)),
+				})
+			}
+
+			response.Reply(fmt.Sprintf("```%s```", formatTable(tableData)))
+		},
+	})
+
 	// Lists all votes stored in the database
-	skr.Command("votes-history <chainName> <startDate> <endDateOptional>", &slacker.CommandDefinition{
-		Description: "lists history of all votes for a given chain",
-		Examples:    []string{"list-votes cosmoshub-4 2023-01-26  2023-02-30"},
+	addCommand(ctx, "votes-history <chainName> <startDate> <endDateOptional>", &slacker.CommandDefinition{
+		Description:       "lists history of all votes for a given chain",
+		Examples:          []string{"votes-history cosmoshub-4 2023-01-26 2023-02-28"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			chainName := request.Param("chainName")
 			startDate := request.Param("startDate")
@@ -225,14 +598,14 @@ func InitializeBotcommands(ctx types.Context) error {
 						slack.NewSectionBlock(
 							slack.NewTextBlockObject(
 								"mrkdwn",
-								fmt.Sprintf("*%s* ---- *%s* ---- Proposal *%s* ---- *%s* ---- *%s*", date, vote.ChainName, vote.ProposalID, vote.ProposalTitle, vote.VoteOption), false, false),
+								fmt.Sprintf("*%s* ---- *%s* ---- *%s* ---- Proposal *%s* ---- *%s* ---- *%s*", date, vote.ChainName, vote.ValidatorAddress, vote.ProposalID, vote.ProposalTitle, vote.VoteOption), false, false),
 							nil, nil,
 						),
 					)
 				}
 
 				attachment := []slack.Block{
-					slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "Date ---- Network ---- ProposalID ---- Proposal Title ---- Vote Option", false, false)),
+					slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "Date ---- Network ---- Validator ---- ProposalID ---- Proposal Title ---- Vote Option", false, false)),
 				}
 				attachment = append(attachment, blocks...)
 
@@ -247,9 +620,10 @@ func InitializeBotcommands(ctx types.Context) error {
 	})
 
 	// Lists all keys stored in the database
-	skr.Command("list-keys", &slacker.CommandDefinition{
-		Description: "lists all keys",
-		Examples:    []string{"list-keys"},
+	addCommand(ctx, "list-keys", &slacker.CommandDefinition{
+		Description:       "lists all keys",
+		Examples:          []string{"list-keys"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			keys, err := ctx.Database().GetKeys()
 			if err != nil {
@@ -281,17 +655,35 @@ func InitializeBotcommands(ctx types.Context) error {
 		},
 	})
 
-	skr.Command("list-proposals", &slacker.CommandDefinition{
-		Description: "lists all proposals",
-		Examples:    []string{"list-proposals"},
+	// Shows a proposal with its messages, tally and the votes of our validators
+	addCommand(ctx, "proposal <chainName> <proposalId>", &slacker.CommandDefinition{
+		Description:       "shows the details, tally and our votes of a proposal",
+		Examples:          []string{"proposal cosmoshub 123"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			details, err := jobs.GetProposalDetails(ctx, request.Param("chainName"), request.Param("proposalId"))
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			response.Reply(formatProposalDetails(details))
+		},
+	})
+
+	addCommand(ctx, "list-proposals", &slacker.CommandDefinition{
+		Description:       "lists all proposals",
+		Examples:          []string{"list-proposals"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
-			jobs.GetProposals(ctx)
+			jobs.ListProposals(ctx)
 		},
 	})
 	// Command to list all registered validators
-	skr.Command("list-validators", &slacker.CommandDefinition{
-		Description: "lists all validators addresses with associated chains",
-		Examples:    []string{"list-validators"},
+	addCommand(ctx, "list-validators", &slacker.CommandDefinition{
+		Description:       "lists all validators addresses with associated chains",
+		Examples:          []string{"list-validators"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
 		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			db := ctx.Database()
 			validators, err := db.GetValidators()
@@ -322,14 +714,53 @@ func InitializeBotcommands(ctx types.Context) error {
 		},
 	})
 
-	ctx1, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// Queries the audit log of the commands and the job actions
+	addCommand(ctx, "audit-log <filtersOptional>", &slacker.CommandDefinition{
+		Description:       "lists the audit log of commands and job actions, filtered by time range, user or chain",
+		Examples:          []string{"audit-log from=2024-05-01 to=2024-05-31 user=@alice chain=cosmoshub limit=20"},
+		AuthorizationFunc: requireRole(ctx, config.RoleViewer),
+		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			args, err := parseCommandArgs(botCtx.Event().Text, "audit-log", fromFlag, toFlag, userFlag, chainFlag, limitFlag)
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+			if len(args.positional) > 0 {
+				response.ReportError(fmt.Errorf("unknown filter %q, filters are given as from=, to=, user=, chain= and limit=", args.positional[0]))
+				return
+			}
 
-	err := skr.Listen(ctx1)
+			filter, err := database.NewAuditFilter(args.flags[fromFlag], args.flags[toFlag], args.flags[userFlag],
+				args.flags[chainFlag], args.flags[limitFlag])
+			if err != nil {
+				response.ReportError(err)
+				return
+			}
+
+			entries, err := ctx.Database().GetAuditEntries(filter)
+			if err != nil {
+				response.ReportError(fmt.Errorf("failed to get the audit log: %v", err))
+				return
+			}
+
+			response.Reply(formatAuditLog(entries))
+		},
+	})
+}
+
+// parseVoteArgs parses the arguments of a vote command from the message and
+// checks that the required positional arguments are given.
+func parseVoteArgs(botCtx slacker.BotContext, command string, required int) (commandArgs, error) {
+	args, err := parseCommandArgs(botCtx.Event().Text, command, memoFlag, metadataFlag, gasPricesFlag)
 	if err != nil {
-		return fmt.Errorf("%s", err)
+		return args, err
 	}
-	return err
+
+	if len(args.positional) < required {
+		return args, fmt.Errorf("%s expects at least %d arguments, got %d", command, required, len(args.positional))
+	}
+
+	return args, nil
 }
 
 func formatTable(data [][]string) string {
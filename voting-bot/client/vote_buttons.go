@@ -0,0 +1,170 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/jobs"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// voteButtonCommand is the command of button votes in the audit log
+const voteButtonCommand = "vote-button"
+
+// votesInProgress holds the block ids of the vote buttons whose vote is
+// being executed, so a second click does not vote twice
+var votesInProgress sync.Map
+
+// handleInteraction acknowledges the interactive events of Slack, votes when
+// a vote button of a proposal alert is clicked and confirms or cancels
+// simulated votes
+func handleInteraction(ctx types.Context, botCtx slacker.InteractiveBotContext, callback *slack.InteractionCallback) {
+	if event := botCtx.Event(); event != nil && event.Request != nil {
+		botCtx.SocketModeClient().Ack(*event.Request)
+	}
+
+	if callback.Type != slack.InteractionTypeBlockActions {
+		return
+	}
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.ActionID == voting.ConfirmVoteActionID || action.ActionID == voting.CancelVoteActionID {
+			if err := handleConfirmationButton(ctx, callback, action); err != nil {
+				log.Printf("failed to confirm vote: %v", err)
+				postEphemeral(ctx, callback, fmt.Sprintf("*Error:* _%s_", err.Error()))
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(action.ActionID, jobs.VoteButtonActionPrefix) {
+			continue
+		}
+
+		attempt := fmt.Sprintf("%s vote button of %s", action.Value, callback.Message.Timestamp)
+		if !isAuthorized(ctx, callback.User.ID, config.RoleVoter, attempt) {
+			voting.AddAuditEntry(ctx, database.AuditEntry{
+				UserID:  callback.User.ID,
+				Command: voteButtonCommand,
+				Params:  action.Value,
+				Result:  database.AuditResultDenied,
+			})
+			postEphemeral(ctx, callback, "*Error:* _you are not authorized to vote_")
+			continue
+		}
+
+		if err := handleVoteButton(ctx, callback, action); err != nil {
+			log.Printf("failed to vote from button: %v", err)
+			postEphemeral(ctx, callback, fmt.Sprintf("*Error:* _%s_", err.Error()))
+		}
+	}
+}
+
+// handleVoteButton submits the vote of the button for the validator of the
+// alert like the vote command: it is simulated and stored as a vote request,
+// which the user confirms in the thread of the alert or, if approvals are
+// required, other users approve. The click does not broadcast the vote. The
+// buttons are replaced once the request is stored and kept if it fails.
+func handleVoteButton(ctx types.Context, callback *slack.InteractionCallback, action *slack.BlockAction) (err error) {
+	userID := callback.User.ID
+	entry := database.AuditEntry{UserID: userID, Command: voteButtonCommand, Params: action.Value}
+	response := voting.NewAuditWriter(callbackThreadWriter(ctx, callback))
+	defer func() {
+		entry = response.Entry(entry)
+		if err != nil {
+			entry.Result = fmt.Sprintf("%s: %v", database.AuditResultError, err)
+		}
+		voting.AddAuditEntry(ctx, entry)
+	}()
+
+	valAddr, proposalID, option, err := jobs.ParseVoteButtonValue(action.Value)
+	if err != nil {
+		return err
+	}
+
+	if _, running := votesInProgress.LoadOrStore(action.BlockID, true); running {
+		return fmt.Errorf("a vote on proposal %s for %s is already in progress", proposalID, valAddr)
+	}
+	defer votesInProgress.Delete(action.BlockID)
+
+	chainName, _, _, err := voting.GetVoteTargets(ctx, valAddr)
+	if err != nil {
+		return err
+	}
+	entry.ChainName = chainName
+
+	id, err := voting.SubmitVote(ctx, database.VoteRequest{
+		Target:      valAddr,
+		ProposalID:  proposalID,
+		VoteType:    database.VoteTypeSingle,
+		VoteOption:  option,
+		GasPrices:   ctx.Config().GasPrices[chainName],
+		RequestedBy: userID,
+	}, false, response)
+	if err != nil {
+		// the error is reported in the thread
+		return nil
+	}
+
+	text := fmt.Sprintf(":hourglass: <@%s> simulated a *%s* vote for %s, confirm vote request #%d in the thread", userID, option, valAddr, id)
+	if ctx.Config().Approval.RequiredApprovals > 0 {
+		text = fmt.Sprintf(":hourglass: <@%s> requested a *%s* vote for %s, vote request #%d is waiting for approvals", userID, option, valAddr, id)
+	}
+	err = updateVoteButtons(ctx, callback, action.BlockID, text)
+
+	// the buttons were replaced in the blocks of the callback, which may
+	// hold an outdated status of the proposal
+	if err := voting.UpdateProposalThread(ctx, chainName, proposalID); err != nil {
+		log.Printf("failed to update thread of %s proposal %s: %v", chainName, proposalID, err)
+	}
+
+	return err
+}
+
+// updateVoteButtons replaces the buttons of the block in the message with
+// the text
+func updateVoteButtons(ctx types.Context, callback *slack.InteractionCallback, blockID, text string) error {
+	var blocks []slack.Block
+	for _, block := range callback.Message.Blocks.BlockSet {
+		if action, ok := block.(*slack.ActionBlock); ok && action.BlockID == blockID {
+			block = slack.NewContextBlock(blockID, slack.NewTextBlockObject("mrkdwn", text, false, false))
+		}
+		blocks = append(blocks, block)
+	}
+
+	_, _, _, err := ctx.Slacker().APIClient().UpdateMessage(
+		callback.Channel.ID,
+		callback.Message.Timestamp,
+		slack.MsgOptionText(callback.Message.Text, false),
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update the buttons: %v", err)
+	}
+
+	return nil
+}
+
+func postEphemeral(ctx types.Context, callback *slack.InteractionCallback, text string) {
+	_, err := ctx.Slacker().APIClient().PostEphemeral(callback.Channel.ID, callback.User.ID, slack.MsgOptionText(text, false))
+	if err != nil {
+		log.Printf("failed to post message to %s: %v", callback.User.ID, err)
+	}
+}
+
+// callbackThreadWriter returns a response writer which posts in the thread
+// of the message whose button was clicked
+func callbackThreadWriter(ctx types.Context, callback *slack.InteractionCallback) slacker.ResponseWriter {
+	threadTS := callback.Message.ThreadTimestamp
+	if threadTS == "" {
+		threadTS = callback.Message.Timestamp
+	}
+
+	return voting.NewThreadResponseWriter(ctx, callback.Channel.ID, threadTS)
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"gopkg.in/go-playground/validator.v9"
@@ -15,12 +17,147 @@ type (
 		ChannelID string `mapstructure:"slack_channel_id"`
 	}
 
+	// Vote approval config details
+	ApprovalConfig struct {
+		// Number of users, other than the requester, who must approve a vote
+		// before it is broadcasted. Votes are broadcasted at once if it is 0.
+		RequiredApprovals int `mapstructure:"required_approvals" validate:"gte=0"`
+		// Slack user IDs allowed to approve votes
+		Approvers []string `mapstructure:"approvers"`
+		// Vote requests expire if they are not approved within the timeout
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+
+	// Fallback vote policy of a chain. The vote is cast on proposals the
+	// validators of the chain have not voted on shortly before the voting
+	// period ends.
+	FallbackVoteConfig struct {
+		ChainName  string `mapstructure:"chain_name" validate:"required"`
+		VoteOption string `mapstructure:"vote_option" validate:"required"`
+		// The vote is cast this long before the end of the voting period
+		BeforeEnd time.Duration `mapstructure:"before_end" validate:"gt=0"`
+		GasPrices string        `mapstructure:"gas_prices"`
+	}
+
+	// Voting rule matched against proposals the validators have not voted
+	// on. Every criterion which is set must match, a list matches if any of
+	// its values matches.
+	VotingRuleConfig struct {
+		Name       string   `mapstructure:"name" validate:"required"`
+		ChainNames []string `mapstructure:"chain_names"`
+		// Type URLs of the proposal messages or legacy proposal contents
+		MessageTypes []string `mapstructure:"message_types"`
+		// Keywords matched case insensitively against the proposal title
+		TitleKeywords []string `mapstructure:"title_keywords"`
+		Proposers     []string `mapstructure:"proposers"`
+		VoteOption    string   `mapstructure:"vote_option" validate:"required"`
+		Rationale     string   `mapstructure:"rationale"`
+		// AutoVote casts the vote, otherwise it is suggested in the alert
+		AutoVote  bool   `mapstructure:"auto_vote"`
+		GasPrices string `mapstructure:"gas_prices"`
+	}
+
+	// Deadline reminder config details
+	ReminderConfig struct {
+		// A reminder is sent for unvoted proposals when the time left to
+		// vote drops below each of the thresholds
+		Thresholds []time.Duration `mapstructure:"thresholds"`
+		// Slack user ID, or user group ID starting with S, mentioned in
+		// the reminders sent within the on call window
+		OnCall       string        `mapstructure:"on_call"`
+		OnCallWindow time.Duration `mapstructure:"on_call_window"`
+	}
+
+	// Software upgrade watcher config details
+	UpgradeConfig struct {
+		// A countdown reminder is sent when the estimated time left to an
+		// upgrade drops below each of the thresholds
+		Thresholds []time.Duration `mapstructure:"thresholds"`
+	}
+
+	// Gas settings of the transactions. The gas limit of a transaction is
+	// its simulated gas multiplied by the adjustment.
+	GasConfig struct {
+		VoteAdjustment     float64 `mapstructure:"vote_adjustment" validate:"gte=0"`
+		WithdrawAdjustment float64 `mapstructure:"withdraw_adjustment" validate:"gte=0"`
+	}
+
+	// Settings of the REST API
+	APIConfig struct {
+		// Bearer token of the audit log endpoint, which is disabled without
+		// a token
+		AuditToken string `mapstructure:"audit_token"`
+	}
+
+	// Role of a Slack user. Every role may also run the commands of the
+	// roles below it.
+	Role string
+
+	// Access control of the Slack commands. Members are Slack user IDs or
+	// user group IDs starting with S. All users may run every command if
+	// no members are configured.
+	AuthorizationConfig struct {
+		Admins  []string `mapstructure:"admins"`
+		Voters  []string `mapstructure:"voters"`
+		Viewers []string `mapstructure:"viewers"`
+	}
+
+	// Slack users and user groups which have a role
+	RoleMembers struct {
+		Role    Role
+		Members []string
+	}
+
 	// Config defines all the app configurations
 	Config struct {
-		Slack SlackBotConfig `mapstructure:"slack"`
+		Slack         SlackBotConfig       `mapstructure:"slack"`
+		Approval      ApprovalConfig       `mapstructure:"approval"`
+		Authorization AuthorizationConfig  `mapstructure:"authorization"`
+		Reminders     ReminderConfig       `mapstructure:"reminders"`
+		Upgrades      UpgradeConfig        `mapstructure:"upgrades"`
+		Gas           GasConfig            `mapstructure:"gas"`
+		API           APIConfig            `mapstructure:"api"`
+		FallbackVotes []FallbackVoteConfig `mapstructure:"fallback_votes" validate:"dive"`
+		VotingRules   []VotingRuleConfig   `mapstructure:"voting_rules" validate:"dive"`
+		// Gas prices by chain name of votes which are not given gas
+		// prices, such as the votes of the buttons of proposal alerts
+		GasPrices map[string]string `mapstructure:"gas_prices"`
 	}
 )
 
+// Roles of the Slack users, from the least to the most privileged
+const (
+	RoleViewer Role = "viewer"
+	RoleVoter  Role = "voter"
+	RoleAdmin  Role = "admin"
+)
+
+// roleRanks orders the roles, a role includes the roles of lower ranks
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleVoter:  2,
+	RoleAdmin:  3,
+}
+
+// defaultApprovalTimeout is used if no timeout is configured
+const defaultApprovalTimeout = 24 * time.Hour
+
+// Defaults of the deadline reminders
+var (
+	defaultReminderThresholds = []time.Duration{72 * time.Hour, 24 * time.Hour, 6 * time.Hour, time.Hour}
+	defaultOnCallWindow       = 6 * time.Hour
+)
+
+// defaultUpgradeThresholds are used if no countdown thresholds of upgrades
+// are configured
+var defaultUpgradeThresholds = []time.Duration{24 * time.Hour, 6 * time.Hour, time.Hour, 15 * time.Minute}
+
+// Gas adjustments used if none are configured
+const (
+	defaultVoteGasAdjustment     = 1.4
+	defaultWithdrawGasAdjustment = 2.1
+)
+
 // ReadConfigFromFile to read config details using viper
 func ReadConfigFromFile() (*Config, error) {
 	v := viper.New()
@@ -40,6 +177,35 @@ func ReadConfigFromFile() (*Config, error) {
 		return nil, fmt.Errorf("error occurred in config validation: %v", err)
 	}
 
+	if cfg.Approval.RequiredApprovals > len(cfg.Approval.Approvers) {
+		return nil, fmt.Errorf("error occurred in config validation: %d approvals are required but only %d approvers are configured",
+			cfg.Approval.RequiredApprovals, len(cfg.Approval.Approvers))
+	}
+
+	if cfg.Approval.Timeout <= 0 {
+		cfg.Approval.Timeout = defaultApprovalTimeout
+	}
+
+	if len(cfg.Reminders.Thresholds) == 0 {
+		cfg.Reminders.Thresholds = defaultReminderThresholds
+	}
+
+	if cfg.Reminders.OnCallWindow <= 0 {
+		cfg.Reminders.OnCallWindow = defaultOnCallWindow
+	}
+
+	if len(cfg.Upgrades.Thresholds) == 0 {
+		cfg.Upgrades.Thresholds = defaultUpgradeThresholds
+	}
+
+	if cfg.Gas.VoteAdjustment == 0 {
+		cfg.Gas.VoteAdjustment = defaultVoteGasAdjustment
+	}
+
+	if cfg.Gas.WithdrawAdjustment == 0 {
+		cfg.Gas.WithdrawAdjustment = defaultWithdrawGasAdjustment
+	}
+
 	return &cfg, nil
 }
 
@@ -51,3 +217,59 @@ func (c *Config) Validate(e ...string) error {
 	}
 	return v.StructExcept(c, e...)
 }
+
+// IsApprover returns true if the Slack user is allowed to approve votes
+func (a ApprovalConfig) IsApprover(userID string) bool {
+	for _, approver := range a.Approvers {
+		if approver == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows returns true if the role may run the commands of the required role
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[required]
+}
+
+// Enabled returns true if any members are configured, otherwise the
+// commands are not restricted
+func (a AuthorizationConfig) Enabled() bool {
+	return len(a.Admins)+len(a.Voters)+len(a.Viewers) > 0
+}
+
+// Members returns the members of the roles, from the most to the least
+// privileged role
+func (a AuthorizationConfig) Members() []RoleMembers {
+	return []RoleMembers{
+		{RoleAdmin, a.Admins},
+		{RoleVoter, a.Voters},
+		{RoleViewer, a.Viewers},
+	}
+}
+
+// FallbackVote returns the fallback vote policy of the chain, if it has one
+func (c *Config) FallbackVote(chainName string) (FallbackVoteConfig, bool) {
+	for _, fallback := range c.FallbackVotes {
+		if fallback.ChainName == chainName {
+			return fallback, true
+		}
+	}
+
+	return FallbackVoteConfig{}, false
+}
+
+// OnCallMention returns the Slack mention of the on call user or user group,
+// or an empty string if none is configured
+func (r ReminderConfig) OnCallMention() string {
+	switch {
+	case r.OnCall == "":
+		return ""
+	case strings.HasPrefix(r.OnCall, "S"):
+		return fmt.Sprintf("<!subteam^%s>", r.OnCall)
+	default:
+		return fmt.Sprintf("<@%s>", r.OnCall)
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"time"
+)
+
+// ActiveProposal is a proposal in voting period, as last seen by the new
+// proposals job
+type ActiveProposal struct {
+	ChainName     string
+	ProposalID    string
+	Title         string
+	VotingEndTime string
+	UpdatedAt     int64
+}
+
+// Replaces the active proposals of the chain
+func (a *Sqlitedb) SetActiveProposals(chainName string, proposals []ActiveProposal) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM active_proposals WHERE chainName = ?", chainName); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Unix()
+	for _, proposal := range proposals {
+		_, err := tx.Exec("INSERT OR REPLACE INTO active_proposals(chainName, proposalId, title, votingEndTime, updatedAt) values(?,?,?,?,?)",
+			chainName, proposal.ProposalID, proposal.Title, proposal.VotingEndTime, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Gets the active proposals of all chains, the proposals ending first come
+// first
+func (a *Sqlitedb) GetActiveProposals() ([]ActiveProposal, error) {
+	rows, err := a.db.Query("SELECT chainName, proposalId, title, votingEndTime, updatedAt FROM active_proposals ORDER BY chainName, votingEndTime")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proposals []ActiveProposal
+	for rows.Next() {
+		var data ActiveProposal
+		if err := rows.Scan(&data.ChainName, &data.ProposalID, &data.Title, &data.VotingEndTime, &data.UpdatedAt); err != nil {
+			return proposals, err
+		}
+		proposals = append(proposals, data)
+	}
+
+	return proposals, rows.Err()
+}
+
+// Gets the last withdrawal of every validator on every chain
+func (a *Sqlitedb) GetLastWithdrawals() ([]RewardsCommission, error) {
+	rows, err := a.db.Query("SELECT chainId, denom, valAddress, rewards, commission, MAX(date) FROM income GROUP BY chainId, valAddress ORDER BY chainId, valAddress")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var withdrawals []RewardsCommission
+	for rows.Next() {
+		var data RewardsCommission
+		if err := rows.Scan(&data.ChainID, &data.Denom, &data.ValAddr, &data.Rewards, &data.Commission, &data.Date); err != nil {
+			return withdrawals, err
+		}
+		withdrawals = append(withdrawals, data)
+	}
+
+	return withdrawals, rows.Err()
+}
+
+// Stores a user who opened the App Home of the bot, so it is refreshed for
+// the user
+func (a *Sqlitedb) AddAppHomeUser(userID string) error {
+	_, err := a.db.Exec("INSERT OR REPLACE INTO app_home_users(userId, openedAt) values(?,?)", userID, time.Now().UTC().Unix())
+	return err
+}
+
+// Removes a user whose App Home is no longer refreshed
+func (a *Sqlitedb) RemoveAppHomeUser(userID string) error {
+	_, err := a.db.Exec("DELETE FROM app_home_users WHERE userId = ?", userID)
+	return err
+}
+
+// Gets the users who opened the App Home of the bot
+func (a *Sqlitedb) GetAppHomeUsers() ([]string, error) {
+	rows, err := a.db.Query("SELECT userId FROM app_home_users ORDER BY openedAt")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []string
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			return users, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
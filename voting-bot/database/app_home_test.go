@@ -0,0 +1,81 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveProposals(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	err := sqlitedb.SetActiveProposals("chain1", []ActiveProposal{
+		{ProposalID: "1", Title: "Upgrade", VotingEndTime: "2023-06-02T00:00:00Z"},
+		{ProposalID: "2", Title: "Spend", VotingEndTime: "2023-06-01T00:00:00Z"},
+	})
+	assert.NoError(t, err)
+	err = sqlitedb.SetActiveProposals("chain2", []ActiveProposal{{ProposalID: "7", Title: "Params"}})
+	assert.NoError(t, err)
+
+	proposals, err := sqlitedb.GetActiveProposals()
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 3)
+	assert.Equal(t, "2", proposals[0].ProposalID)
+	assert.Equal(t, "chain1", proposals[0].ChainName)
+	assert.Equal(t, "1", proposals[1].ProposalID)
+	assert.Equal(t, "chain2", proposals[2].ChainName)
+
+	// the proposals of a chain are replaced, other chains are kept
+	err = sqlitedb.SetActiveProposals("chain1", []ActiveProposal{{ProposalID: "1", Title: "Upgrade"}})
+	assert.NoError(t, err)
+	proposals, err = sqlitedb.GetActiveProposals()
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 2)
+	assert.Equal(t, "1", proposals[0].ProposalID)
+	assert.Equal(t, "7", proposals[1].ProposalID)
+
+	assert.NoError(t, sqlitedb.SetActiveProposals("chain1", nil))
+	proposals, err = sqlitedb.GetActiveProposals()
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 1)
+}
+
+func TestLastWithdrawals(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	// rows are inserted directly, AddRewards stores the current date
+	rows := [][]string{
+		{"chain-1", "uatom", "val1", "10", "1", "2023-04-01"},
+		{"chain-1", "uatom", "val1", "20", "2", "2023-05-01"},
+		{"chain-2", "uosmo", "val2", "30", "3", "2023-03-01"},
+	}
+	for _, row := range rows {
+		_, err := sqlitedb.db.Exec("INSERT INTO income(chainId, denom, valAddress, rewards, commission, date) values(?,?,?,?,?,?)",
+			row[0], row[1], row[2], row[3], row[4], row[5])
+		assert.NoError(t, err)
+	}
+
+	withdrawals, err := sqlitedb.GetLastWithdrawals()
+	assert.NoError(t, err)
+	assert.Equal(t, []RewardsCommission{
+		{ChainID: "chain-1", Denom: "uatom", ValAddr: "val1", Rewards: "20", Commission: "2", Date: "2023-05-01"},
+		{ChainID: "chain-2", Denom: "uosmo", ValAddr: "val2", Rewards: "30", Commission: "3", Date: "2023-03-01"},
+	}, withdrawals)
+}
+
+func TestAppHomeUsers(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	assert.NoError(t, sqlitedb.AddAppHomeUser("U1"))
+	assert.NoError(t, sqlitedb.AddAppHomeUser("U2"))
+	assert.NoError(t, sqlitedb.AddAppHomeUser("U1"))
+
+	users, err := sqlitedb.GetAppHomeUsers()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"U1", "U2"}, users)
+
+	assert.NoError(t, sqlitedb.RemoveAppHomeUser("U1"))
+	users, err = sqlitedb.GetAppHomeUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U2"}, users)
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Results of audited commands and actions
+	AuditResultOK     = "ok"
+	AuditResultError  = "error"
+	AuditResultDenied = "denied"
+	// AuditResultSkipped is the result of job actions which were not needed
+	AuditResultSkipped = "skipped"
+)
+
+type (
+	// AuditEntry records a Slack command run by a user or an action taken
+	// by a job
+	AuditEntry struct {
+		ID   int64 `json:"id"`
+		Date int64 `json:"date"`
+		// UserID is the Slack user who ran the command, it is empty for
+		// the actions of jobs
+		UserID string `json:"userId"`
+		// Command is the Slack command or the name of the job action
+		Command   string `json:"command"`
+		ChainName string `json:"chainName"`
+		Params    string `json:"params"`
+		Result    string `json:"result"`
+		TxHash    string `json:"txHash"`
+	}
+
+	// AuditFilter selects audit entries, fields which are not set match all
+	// entries
+	AuditFilter struct {
+		// From and To limit the entries to a range of unix times
+		From      int64
+		To        int64
+		UserID    string
+		ChainName string
+		Limit     int
+	}
+)
+
+// defaultAuditLimit is the number of audit entries returned if no limit is
+// given
+const defaultAuditLimit = 50
+
+// NewAuditFilter parses a filter from the query of the audit log. Times are
+// dates like 2006-01-02 or RFC3339 times, a date given as to includes the
+// whole day. A Slack mention is accepted as the user.
+func NewAuditFilter(from, to, userID, chainName, limit string) (AuditFilter, error) {
+	filter := AuditFilter{
+		UserID:    strings.TrimSuffix(strings.TrimPrefix(strings.Split(userID, "|")[0], "<@"), ">"),
+		ChainName: chainName,
+		Limit:     defaultAuditLimit,
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(from, false); err != nil {
+		return AuditFilter{}, fmt.Errorf("invalid start time %q: %v", from, err)
+	}
+	if filter.To, err = parseAuditTime(to, true); err != nil {
+		return AuditFilter{}, fmt.Errorf("invalid end time %q: %v", to, err)
+	}
+	if filter.To > 0 && filter.From > filter.To {
+		return AuditFilter{}, fmt.Errorf("start time %s is after end time %s", from, to)
+	}
+
+	if limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return AuditFilter{}, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+
+	return filter, nil
+}
+
+func parseAuditTime(value string, endOfDay bool) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return 0, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+
+	return t.Unix(), nil
+}
+
+// Adds an entry to the audit log, Date is set to the current time if it is
+// not given
+func (a *Sqlitedb) AddAuditEntry(entry AuditEntry) error {
+	stmt, err := a.db.Prepare("INSERT INTO audit_log(date, userId, command, chainName, params, result, txHash) values(?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	if entry.Date == 0 {
+		entry.Date = time.Now().UTC().Unix()
+	}
+
+	_, err = stmt.Exec(entry.Date, entry.UserID, entry.Command, entry.ChainName, entry.Params, entry.Result, entry.TxHash)
+	return err
+}
+
+// Gets the audit entries matching the filter, the latest first
+func (a *Sqlitedb) GetAuditEntries(filter AuditFilter) ([]AuditEntry, error) {
+	query := "SELECT id, date, userId, command, chainName, params, result, txHash FROM audit_log WHERE 1 = 1"
+	var args []interface{}
+	if filter.From > 0 {
+		query += " AND date >= ?"
+		args = append(args, filter.From)
+	}
+	if filter.To > 0 {
+		query += " AND date <= ?"
+		args = append(args, filter.To)
+	}
+	if filter.UserID != "" {
+		query += " AND userId = ?"
+		args = append(args, filter.UserID)
+	}
+	if filter.ChainName != "" {
+		query += " AND chainName = ?"
+		args = append(args, filter.ChainName)
+	}
+	query += " ORDER BY date DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	for rows.Next() {
+		var data AuditEntry
+		if err := rows.Scan(&data.ID, &data.Date, &data.UserID, &data.Command, &data.ChainName,
+			&data.Params, &data.Result, &data.TxHash); err != nil {
+			return entries, err
+		}
+		entries = append(entries, data)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	entries := []AuditEntry{
+		{Date: 100, UserID: "U1", Command: "vote", ChainName: "chain1", Params: "chain1 5 yes", Result: AuditResultOK, TxHash: "ABC"},
+		{Date: 200, UserID: "U2", Command: "create-key", ChainName: "chain2", Params: "chain2 voting", Result: AuditResultDenied},
+		{Date: 300, Command: "fallback-vote", ChainName: "chain1", Params: "abstain on proposal 6", Result: AuditResultError},
+	}
+	for _, entry := range entries {
+		assert.NoError(t, sqlitedb.AddAuditEntry(entry))
+	}
+
+	all, err := sqlitedb.GetAuditEntries(AuditFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+	assert.Equal(t, "fallback-vote", all[0].Command)
+	assert.Equal(t, "ABC", all[2].TxHash)
+
+	byUser, err := sqlitedb.GetAuditEntries(AuditFilter{UserID: "U1"})
+	assert.NoError(t, err)
+	assert.Len(t, byUser, 1)
+	assert.Equal(t, "vote", byUser[0].Command)
+
+	byChain, err := sqlitedb.GetAuditEntries(AuditFilter{ChainName: "chain1", From: 150})
+	assert.NoError(t, err)
+	assert.Len(t, byChain, 1)
+	assert.Equal(t, int64(300), byChain[0].Date)
+
+	inRange, err := sqlitedb.GetAuditEntries(AuditFilter{From: 100, To: 200})
+	assert.NoError(t, err)
+	assert.Len(t, inRange, 2)
+
+	limited, err := sqlitedb.GetAuditEntries(AuditFilter{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, limited, 1)
+
+	// entries without a date get the current time
+	assert.NoError(t, sqlitedb.AddAuditEntry(AuditEntry{UserID: "U1", Command: "list-keys", Result: AuditResultOK}))
+	latest, err := sqlitedb.GetAuditEntries(AuditFilter{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "list-keys", latest[0].Command)
+	assert.Greater(t, latest[0].Date, int64(300))
+}
+
+func TestNewAuditFilter(t *testing.T) {
+	filter, err := NewAuditFilter("2024-05-01", "2024-05-01", "<@U123|alice>", "chain1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1714521600), filter.From)
+	assert.Equal(t, int64(1714521600+24*3600-1), filter.To)
+	assert.Equal(t, "U123", filter.UserID)
+	assert.Equal(t, "chain1", filter.ChainName)
+	assert.Equal(t, defaultAuditLimit, filter.Limit)
+
+	filter, err = NewAuditFilter("2024-05-01T12:00:00Z", "", "U123", "", "10")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1714564800), filter.From)
+	assert.Equal(t, int64(0), filter.To)
+	assert.Equal(t, "U123", filter.UserID)
+	assert.Equal(t, 10, filter.Limit)
+
+	_, err = NewAuditFilter("yesterday", "", "", "", "")
+	assert.Error(t, err)
+
+	_, err = NewAuditFilter("2024-05-02", "2024-05-01", "", "", "")
+	assert.Error(t, err)
+
+	_, err = NewAuditFilter("", "", "", "", "0")
+	assert.Error(t, err)
+}
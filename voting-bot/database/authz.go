@@ -1,11 +1,16 @@
 package database
 
+import "time"
+
 type AuthzKeys struct {
 	ChainName      string
 	KeyName        string
 	GranteeAddress string
 	AuthzStatus    string
 	Type           string
+	// Balance is the last balance of the key seen by the low balance job
+	Balance          string
+	BalanceUpdatedAt int64
 }
 
 // Stores Keys information
@@ -34,6 +39,13 @@ func (a *Sqlitedb) UpdateAuthzStatus(status, keyAddress, keyType string) error {
 	return err
 }
 
+// Updates the balance of the key
+func (a *Sqlitedb) SetKeyBalance(keyAddress, balance string) error {
+	_, err := a.db.Exec("UPDATE keys SET balance = ?, balanceUpdatedAt = ? WHERE granteeAddress = ?",
+		balance, time.Now().UTC().Unix(), keyAddress)
+	return err
+}
+
 // Gets Key address of a specific key
 func (a *Sqlitedb) GetAuthzKeyAddress(name, keyType string) (string, error) {
 	var addr string
@@ -69,7 +81,7 @@ func (a *Sqlitedb) GetChainKey(chainName, keyType string) (string, error) {
 
 // Gets required data regarding keys
 func (a *Sqlitedb) GetKeys() ([]AuthzKeys, error) {
-	rows, err := a.db.Query("SELECT chainName, keyName, granteeAddress, authzStatus, type FROM keys")
+	rows, err := a.db.Query("SELECT chainName, keyName, granteeAddress, authzStatus, type, balance, balanceUpdatedAt FROM keys")
 	if err != nil {
 		return []AuthzKeys{}, err
 	}
@@ -78,7 +90,7 @@ func (a *Sqlitedb) GetKeys() ([]AuthzKeys, error) {
 	var k []AuthzKeys
 	for rows.Next() {
 		var data AuthzKeys
-		if err := rows.Scan(&data.ChainName, &data.KeyName, &data.GranteeAddress, &data.AuthzStatus, &data.Type); err != nil {
+		if err := rows.Scan(&data.ChainName, &data.KeyName, &data.GranteeAddress, &data.AuthzStatus, &data.Type, &data.Balance, &data.BalanceUpdatedAt); err != nil {
 			return k, err
 		}
 		k = append(k, data)
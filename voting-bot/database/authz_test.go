@@ -13,7 +13,7 @@ func TestKeys(t *testing.T) {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
 	defer db.Close()
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS keys (chainName VARCHAR, keyName VARCHAR, granteeAddress VARCHAR, type VARCHAR,authzStatus VARCHAR DEFAULT 'false', PRIMARY KEY (chainName,type))")
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS keys (chainName VARCHAR, keyName VARCHAR, granteeAddress VARCHAR, type VARCHAR,authzStatus VARCHAR DEFAULT 'false', balance VARCHAR DEFAULT '', balanceUpdatedAt INTEGER DEFAULT 0, PRIMARY KEY (chainName,type))")
 	if err != nil {
 		t.Fatalf("Failed to create test table: %v", err)
 	}
@@ -46,3 +46,26 @@ func TestKeys(t *testing.T) {
 	}
 	assert.Equal(t, expectedLog, logs[0])
 }
+
+func TestKeyBalance(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS keys (chainName VARCHAR, keyName VARCHAR, granteeAddress VARCHAR, type VARCHAR,authzStatus VARCHAR DEFAULT 'false', balance VARCHAR DEFAULT '', balanceUpdatedAt INTEGER DEFAULT 0, PRIMARY KEY (chainName,type))")
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+
+	sqlitedb := &Sqlitedb{db: db}
+
+	assert.NoError(t, sqlitedb.AddAuthzKey("chain1", "keyname", "keyaddress", "voting"))
+	assert.NoError(t, sqlitedb.SetKeyBalance("keyaddress", "12.50ATOM"))
+
+	keys, err := sqlitedb.GetKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "12.50ATOM", keys[0].Balance)
+	assert.NotZero(t, keys[0].BalanceUpdatedAt)
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ChainCapabilities holds the gov versions and the authz support detected
+// on a chain
+type ChainCapabilities struct {
+	ChainName  string
+	GovV1      bool
+	GovV1beta1 bool
+	Authz      bool
+	SDKVersion string
+	CheckedAt  int64
+}
+
+// Gets the capabilities detected on the chain, found is false if the chain
+// was not probed yet
+func (a *Sqlitedb) GetChainCapabilities(chainName string) (ChainCapabilities, bool, error) {
+	var caps ChainCapabilities
+	err := a.db.QueryRow("SELECT chainName, govV1, govV1beta1, authz, sdkVersion, checkedAt FROM chain_capabilities WHERE chainName = ?", chainName).
+		Scan(&caps.ChainName, &caps.GovV1, &caps.GovV1beta1, &caps.Authz, &caps.SDKVersion, &caps.CheckedAt)
+	if err == sql.ErrNoRows {
+		return ChainCapabilities{}, false, nil
+	}
+	if err != nil {
+		return ChainCapabilities{}, false, err
+	}
+
+	return caps, true, nil
+}
+
+// Stores the capabilities detected on the chain along with the time they
+// were checked
+func (a *Sqlitedb) SetChainCapabilities(caps ChainCapabilities) error {
+	stmt, err := a.db.Prepare("INSERT OR REPLACE INTO chain_capabilities(chainName, govV1, govV1beta1, authz, sdkVersion, checkedAt) values(?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(caps.ChainName, caps.GovV1, caps.GovV1beta1, caps.Authz, caps.SDKVersion, time.Now().UTC().Unix())
+	return err
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainCapabilities(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, found, err := sqlitedb.GetChainCapabilities("cosmoshub")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	err = sqlitedb.SetChainCapabilities(ChainCapabilities{
+		ChainName:  "cosmoshub",
+		GovV1beta1: true,
+		Authz:      true,
+		SDKVersion: "v0.45.16",
+	})
+	assert.NoError(t, err)
+
+	caps, found, err := sqlitedb.GetChainCapabilities("cosmoshub")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.False(t, caps.GovV1)
+	assert.True(t, caps.GovV1beta1)
+	assert.True(t, caps.Authz)
+	assert.Equal(t, "v0.45.16", caps.SDKVersion)
+	assert.NotZero(t, caps.CheckedAt)
+
+	// a refresh replaces the stored capabilities
+	err = sqlitedb.SetChainCapabilities(ChainCapabilities{
+		ChainName:  "cosmoshub",
+		GovV1:      true,
+		GovV1beta1: true,
+		Authz:      true,
+		SDKVersion: "v0.47.13",
+	})
+	assert.NoError(t, err)
+
+	caps, _, err = sqlitedb.GetChainCapabilities("cosmoshub")
+	assert.NoError(t, err)
+	assert.True(t, caps.GovV1)
+	assert.Equal(t, "v0.47.13", caps.SDKVersion)
+}
@@ -0,0 +1,43 @@
+package database
+
+// Statuses of the job runs
+const (
+	JobRunOK    = "ok"
+	JobRunError = "error"
+)
+
+// JobRun is the last run of a cron job
+type JobRun struct {
+	Name       string
+	StartedAt  int64
+	FinishedAt int64
+	Status     string
+	Error      string
+}
+
+// Stores the run of the job, replacing its previous run
+func (a *Sqlitedb) SetJobRun(run JobRun) error {
+	_, err := a.db.Exec("INSERT OR REPLACE INTO job_runs(name, startedAt, finishedAt, status, error) values(?,?,?,?,?)",
+		run.Name, run.StartedAt, run.FinishedAt, run.Status, run.Error)
+	return err
+}
+
+// Gets the last run of every job
+func (a *Sqlitedb) GetJobRuns() ([]JobRun, error) {
+	rows, err := a.db.Query("SELECT name, startedAt, finishedAt, status, error FROM job_runs ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []JobRun
+	for rows.Next() {
+		var data JobRun
+		if err := rows.Scan(&data.Name, &data.StartedAt, &data.FinishedAt, &data.Status, &data.Error); err != nil {
+			return runs, err
+		}
+		runs = append(runs, data)
+	}
+
+	return runs, rows.Err()
+}
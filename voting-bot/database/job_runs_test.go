@@ -0,0 +1,24 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRuns(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	assert.NoError(t, sqlitedb.SetJobRun(JobRun{Name: "withdraw-rewards", StartedAt: 100, FinishedAt: 110, Status: JobRunError, Error: "timeout"}))
+	assert.NoError(t, sqlitedb.SetJobRun(JobRun{Name: "alert-new-proposals", StartedAt: 100, FinishedAt: 105, Status: JobRunOK}))
+
+	// only the last run of a job is kept
+	assert.NoError(t, sqlitedb.SetJobRun(JobRun{Name: "withdraw-rewards", StartedAt: 200, FinishedAt: 220, Status: JobRunOK}))
+
+	runs, err := sqlitedb.GetJobRuns()
+	assert.NoError(t, err)
+	assert.Equal(t, []JobRun{
+		{Name: "alert-new-proposals", StartedAt: 100, FinishedAt: 105, Status: JobRunOK},
+		{Name: "withdraw-rewards", StartedAt: 200, FinishedAt: 220, Status: JobRunOK},
+	}, runs)
+}
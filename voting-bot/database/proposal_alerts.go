@@ -0,0 +1,61 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProposalAlert is the alert state of a proposal for a validator
+type ProposalAlert struct {
+	ChainName        string
+	ValidatorAddress string
+	ProposalID       string
+	FirstSeen        int64
+	// LastAlerted is 0 if no alert was sent yet
+	LastAlerted int64
+}
+
+// Stores a proposal seen for the validator. It returns true if the proposal
+// was not seen before.
+func (a *Sqlitedb) AddProposalAlert(chainName, validatorAddress, proposalID string) (bool, error) {
+	res, err := a.db.Exec("INSERT OR IGNORE INTO proposal_alerts(chainName, validatorAddress, proposalId, firstSeen, lastAlerted) values(?,?,?,?,0)",
+		chainName, validatorAddress, proposalID, time.Now().UTC().Unix())
+	if err != nil {
+		return false, err
+	}
+
+	added, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return added == 1, nil
+}
+
+// Gets the alert state of the proposal for the validator
+func (a *Sqlitedb) GetProposalAlert(chainName, validatorAddress, proposalID string) (ProposalAlert, bool, error) {
+	var alert ProposalAlert
+	err := a.db.QueryRow("SELECT chainName, validatorAddress, proposalId, firstSeen, lastAlerted FROM proposal_alerts WHERE chainName = ? AND validatorAddress = ? AND proposalId = ?",
+		chainName, validatorAddress, proposalID).Scan(&alert.ChainName, &alert.ValidatorAddress, &alert.ProposalID, &alert.FirstSeen, &alert.LastAlerted)
+	if err == sql.ErrNoRows {
+		return ProposalAlert{}, false, nil
+	}
+	if err != nil {
+		return ProposalAlert{}, false, err
+	}
+
+	return alert, true, nil
+}
+
+// Records that an alert on the proposal was sent for the validator
+func (a *Sqlitedb) SetProposalAlerted(chainName, validatorAddress, proposalID string) error {
+	stmt, err := a.db.Prepare("UPDATE proposal_alerts SET lastAlerted = ? WHERE chainName = ? AND validatorAddress = ? AND proposalId = ?")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(time.Now().UTC().Unix(), chainName, validatorAddress, proposalID)
+	return err
+}
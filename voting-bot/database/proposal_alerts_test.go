@@ -0,0 +1,44 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposalAlerts(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, found, err := sqlitedb.GetProposalAlert("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	isNew, err := sqlitedb.AddProposalAlert("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+
+	// a proposal is only new the first time it is seen
+	isNew, err = sqlitedb.AddProposalAlert("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.False(t, isNew)
+
+	isNew, err = sqlitedb.AddProposalAlert("chain1", "val2", "1")
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+
+	alert, found, err := sqlitedb.GetProposalAlert("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NotZero(t, alert.FirstSeen)
+	assert.Zero(t, alert.LastAlerted)
+
+	assert.NoError(t, sqlitedb.SetProposalAlerted("chain1", "val1", "1"))
+
+	alert, _, err = sqlitedb.GetProposalAlert("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.NotZero(t, alert.LastAlerted)
+
+	alert, _, err = sqlitedb.GetProposalAlert("chain1", "val2", "1")
+	assert.NoError(t, err)
+	assert.Zero(t, alert.LastAlerted)
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Final outcomes of a proposal. A failed proposal passed but its messages
+	// failed to execute.
+	OutcomePassed   = "passed"
+	OutcomeRejected = "rejected"
+	OutcomeVetoed   = "vetoed"
+	OutcomeFailed   = "failed"
+	// OutcomeNotFound is stored for proposals the chain no longer returns, so
+	// they are not revisited
+	OutcomeNotFound = "not found"
+)
+
+type (
+	// ProposalOutcome holds the final status and tally of a proposal
+	ProposalOutcome struct {
+		ChainName     string `json:"chainName"`
+		ProposalID    string `json:"proposalID"`
+		Status        string `json:"status"`
+		Outcome       string `json:"outcome"`
+		Yes           string `json:"yes"`
+		Abstain       string `json:"abstain"`
+		No            string `json:"no"`
+		NoWithVeto    string `json:"noWithVeto"`
+		VotingEndTime string `json:"votingEndTime"`
+		RecordedAt    int64  `json:"recordedAt"`
+	}
+
+	// PendingOutcome is a proposal from the vote logs whose outcome is not
+	// recorded yet
+	PendingOutcome struct {
+		ChainName  string
+		ProposalID string
+	}
+
+	// AlignmentStats counts how the votes of a validator compare with the
+	// outcomes of the finished proposals of its chain
+	AlignmentStats struct {
+		ChainName        string `json:"chainName"`
+		ValidatorAddress string `json:"validatorAddress"`
+		Proposals        int    `json:"proposals"`
+		Voted            int    `json:"voted"`
+		Aligned          int    `json:"aligned"`
+		Abstained        int    `json:"abstained"`
+		Missed           int    `json:"missed"`
+		// Alignment is the share of the yes and no votes which matched the
+		// outcome
+		Alignment float64 `json:"alignment"`
+	}
+)
+
+// Stores the final outcome of a proposal
+func (a *Sqlitedb) AddProposalOutcome(outcome ProposalOutcome) error {
+	stmt, err := a.db.Prepare("INSERT OR REPLACE INTO proposal_outcomes(chainName, proposalId, status, outcome, yes, abstain, no, noWithVeto, votingEndTime, recordedAt) values(?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(outcome.ChainName, outcome.ProposalID, outcome.Status, outcome.Outcome, outcome.Yes, outcome.Abstain,
+		outcome.No, outcome.NoWithVeto, outcome.VotingEndTime, time.Now().UTC().Unix())
+	return err
+}
+
+// Gets the proposals of the vote logs whose outcome is not recorded yet
+func (a *Sqlitedb) GetPendingOutcomes() ([]PendingOutcome, error) {
+	rows, err := a.db.Query(`SELECT DISTINCT l.chainName, l.proposalId FROM logs l
+		LEFT JOIN proposal_outcomes o ON o.chainName = l.chainName AND o.proposalId = l.proposalId
+		WHERE o.proposalId IS NULL ORDER BY l.chainName, l.proposalId`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []PendingOutcome
+	for rows.Next() {
+		var data PendingOutcome
+		if err := rows.Scan(&data.ChainName, &data.ProposalID); err != nil {
+			return pending, err
+		}
+		pending = append(pending, data)
+	}
+
+	return pending, rows.Err()
+}
+
+// Gets the recorded outcomes of the proposals on the chain, or on all chains
+// if chainName is empty
+func (a *Sqlitedb) GetProposalOutcomes(chainName string) ([]ProposalOutcome, error) {
+	query := "SELECT chainName, proposalId, status, outcome, yes, abstain, no, noWithVeto, votingEndTime, recordedAt FROM proposal_outcomes"
+	var args []interface{}
+	if chainName != "" {
+		query += " WHERE chainName = ?"
+		args = append(args, chainName)
+	}
+	query += " ORDER BY chainName, CAST(proposalId AS INTEGER)"
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []ProposalOutcome
+	for rows.Next() {
+		var data ProposalOutcome
+		if err := rows.Scan(&data.ChainName, &data.ProposalID, &data.Status, &data.Outcome, &data.Yes, &data.Abstain,
+			&data.No, &data.NoWithVeto, &data.VotingEndTime, &data.RecordedAt); err != nil {
+			return outcomes, err
+		}
+		outcomes = append(outcomes, data)
+	}
+
+	return outcomes, rows.Err()
+}
+
+// Gets the alignment of the votes of each validator with the outcomes of the
+// finished proposals, on the chain or on all chains if chainName is empty.
+// A proposal the validator has no vote logged on counts as missed.
+func (a *Sqlitedb) GetAlignmentStats(chainName string) ([]AlignmentStats, error) {
+	query := `SELECT l.chainName, l.validatorAddress, l.voteOption, o.outcome FROM logs l
+		JOIN proposal_outcomes o ON o.chainName = l.chainName AND o.proposalId = l.proposalId
+		WHERE o.outcome != ?`
+	args := []interface{}{OutcomeNotFound}
+	if chainName != "" {
+		query += " AND l.chainName = ?"
+		args = append(args, chainName)
+	}
+	query += " ORDER BY l.chainName, l.validatorAddress"
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []AlignmentStats
+	for rows.Next() {
+		var chain, validator, voteOption, outcome string
+		if err := rows.Scan(&chain, &validator, &voteOption, &outcome); err != nil {
+			return stats, err
+		}
+
+		if len(stats) == 0 || stats[len(stats)-1].ChainName != chain || stats[len(stats)-1].ValidatorAddress != validator {
+			stats = append(stats, AlignmentStats{ChainName: chain, ValidatorAddress: validator})
+		}
+
+		s := &stats[len(stats)-1]
+		s.Proposals++
+		switch option := mainVoteOption(voteOption); {
+		case option == "":
+			s.Missed++
+		case option == "ABSTAIN":
+			s.Voted++
+			s.Abstained++
+		default:
+			s.Voted++
+			if isAligned(option, outcome) {
+				s.Aligned++
+			}
+		}
+	}
+
+	for i := range stats {
+		if decisive := stats[i].Voted - stats[i].Abstained; decisive > 0 {
+			stats[i].Alignment = float64(stats[i].Aligned) / float64(decisive)
+		}
+	}
+
+	return stats, rows.Err()
+}
+
+// mainVoteOption returns the option of the vote without the VOTE_OPTION_
+// prefix. Weighted votes, logged as OPTION-weight pairs, return the option
+// with the largest weight.
+func mainVoteOption(voteOption string) string {
+	var option string
+	var weight float64
+	for _, pair := range strings.Split(voteOption, ",") {
+		name, value, weighted := strings.Cut(strings.TrimSpace(pair), "-")
+		if !weighted {
+			option = name
+			break
+		}
+
+		w, err := strconv.ParseFloat(value, 64)
+		if err == nil && w > weight {
+			option, weight = name, w
+		}
+	}
+
+	return strings.TrimPrefix(strings.ToUpper(option), "VOTE_OPTION_")
+}
+
+// isAligned returns true if a yes vote went with a passed proposal, or a no
+// vote with a rejected one. Failed proposals passed the vote.
+func isAligned(option, outcome string) bool {
+	switch outcome {
+	case OutcomePassed, OutcomeFailed:
+		return option == "YES"
+	case OutcomeRejected, OutcomeVetoed:
+		return option == "NO" || option == "NO_WITH_VETO"
+	}
+
+	return false
+}
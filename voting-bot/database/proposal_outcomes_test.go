@@ -0,0 +1,109 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposalOutcomes(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	logs := []struct {
+		chainName, validator, proposalID, voteOption string
+	}{
+		{"chain1", "val1", "1", "VOTE_OPTION_YES"},
+		{"chain1", "val1", "2", "VOTE_OPTION_YES"},
+		{"chain1", "val1", "3", ""},
+		{"chain1", "val1", "4", "VOTE_OPTION_NO-0.300000000000000000,VOTE_OPTION_NO_WITH_VETO-0.700000000000000000"},
+		{"chain1", "val1", "5", "VOTE_OPTION_ABSTAIN"},
+		{"chain1", "val2", "1", ""},
+		{"chain2", "val3", "1", "VOTE_OPTION_NO"},
+	}
+	for _, l := range logs {
+		assert.NoError(t, sqlitedb.AddLog(l.chainName, l.validator, "title", l.proposalID, l.voteOption))
+	}
+
+	pending, err := sqlitedb.GetPendingOutcomes()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 6)
+
+	for _, outcome := range []ProposalOutcome{
+		{ChainName: "chain1", ProposalID: "1", Status: "PROPOSAL_STATUS_PASSED", Outcome: OutcomePassed, Yes: "100"},
+		{ChainName: "chain1", ProposalID: "2", Status: "PROPOSAL_STATUS_REJECTED", Outcome: OutcomeRejected, No: "100"},
+		{ChainName: "chain1", ProposalID: "3", Status: "PROPOSAL_STATUS_PASSED", Outcome: OutcomePassed},
+		{ChainName: "chain1", ProposalID: "4", Status: "PROPOSAL_STATUS_REJECTED", Outcome: OutcomeVetoed},
+		{ChainName: "chain2", ProposalID: "1", Status: "", Outcome: OutcomeNotFound},
+	} {
+		assert.NoError(t, sqlitedb.AddProposalOutcome(outcome))
+	}
+
+	pending, err = sqlitedb.GetPendingOutcomes()
+	assert.NoError(t, err)
+	assert.Equal(t, []PendingOutcome{{ChainName: "chain1", ProposalID: "5"}}, pending)
+
+	outcomes, err := sqlitedb.GetProposalOutcomes("chain1")
+	assert.NoError(t, err)
+	assert.Len(t, outcomes, 4)
+	assert.Equal(t, OutcomeVetoed, outcomes[3].Outcome)
+
+	// proposals without a recorded outcome and proposals not found are left out
+	stats, err := sqlitedb.GetAlignmentStats("")
+	assert.NoError(t, err)
+	assert.Equal(t, []AlignmentStats{
+		{ChainName: "chain1", ValidatorAddress: "val1", Proposals: 4, Voted: 3, Aligned: 2, Missed: 1, Alignment: 2.0 / 3},
+		{ChainName: "chain1", ValidatorAddress: "val2", Proposals: 1, Missed: 1},
+	}, stats)
+
+	stats, err = sqlitedb.GetAlignmentStats("chain2")
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+}
+
+func TestMainVoteOption(t *testing.T) {
+	tests := []struct {
+		voteOption string
+		want       string
+	}{
+		{"VOTE_OPTION_YES", "YES"},
+		{"VOTE_OPTION_NO_WITH_VETO", "NO_WITH_VETO"},
+		{"yes", "YES"},
+		{"VOTE_OPTION_NO-0.300000000000000000,VOTE_OPTION_NO_WITH_VETO-0.700000000000000000", "NO_WITH_VETO"},
+		{"VOTE_OPTION_YES-0.600000000000000000, VOTE_OPTION_ABSTAIN-0.400000000000000000", "YES"},
+		// the first of equal weights is kept
+		{"VOTE_OPTION_YES-0.500000000000000000,VOTE_OPTION_NO-0.500000000000000000", "YES"},
+		{"VOTE_OPTION_YES-abc,VOTE_OPTION_NO-0.1", "NO"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.voteOption, func(t *testing.T) {
+			assert.Equal(t, tt.want, mainVoteOption(tt.voteOption))
+		})
+	}
+}
+
+func TestIsAligned(t *testing.T) {
+	tests := []struct {
+		option  string
+		outcome string
+		want    bool
+	}{
+		{"YES", OutcomePassed, true},
+		{"YES", OutcomeFailed, true},
+		{"NO", OutcomePassed, false},
+		{"NO", OutcomeRejected, true},
+		{"NO_WITH_VETO", OutcomeVetoed, true},
+		{"NO_WITH_VETO", OutcomeRejected, true},
+		{"YES", OutcomeVetoed, false},
+		{"ABSTAIN", OutcomePassed, false},
+		{"ABSTAIN", OutcomeRejected, false},
+		{"YES", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.option+" "+tt.outcome, func(t *testing.T) {
+			assert.Equal(t, tt.want, isAligned(tt.option, tt.outcome))
+		})
+	}
+}
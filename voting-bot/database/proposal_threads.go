@@ -0,0 +1,102 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ThreadVotingPeriod is the status of a proposal thread until the outcome of
+// the proposal is recorded, the outcome is its status from then on
+const ThreadVotingPeriod = "voting period"
+
+type (
+	// ProposalThread is the Slack thread of the first alert on a proposal,
+	// which collects all later activity on the proposal
+	ProposalThread struct {
+		ChainName     string
+		ProposalID    string
+		ChannelID     string
+		ThreadTS      string
+		Title         string
+		VotingEndTime string
+		Status        string
+		CreatedAt     int64
+	}
+
+	// ProposalVote is the vote of a validator on a proposal from the vote
+	// logs, VoteOption is empty if the validator has not voted
+	ProposalVote struct {
+		ValidatorAddress string
+		VoteOption       string
+		TxHash           string
+		Confirmed        bool
+	}
+)
+
+// Stores the thread of the proposal. It returns false if the proposal has a
+// thread already, which is kept.
+func (a *Sqlitedb) AddProposalThread(thread ProposalThread) (bool, error) {
+	res, err := a.db.Exec("INSERT OR IGNORE INTO proposal_threads(chainName, proposalId, channelId, threadTs, title, votingEndTime, status, createdAt) values(?,?,?,?,?,?,?,?)",
+		thread.ChainName, thread.ProposalID, thread.ChannelID, thread.ThreadTS, thread.Title, thread.VotingEndTime,
+		thread.Status, time.Now().UTC().Unix())
+	if err != nil {
+		return false, err
+	}
+
+	added, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return added == 1, nil
+}
+
+// Gets the thread of the proposal, found is false if it has none
+func (a *Sqlitedb) GetProposalThread(chainName, proposalID string) (ProposalThread, bool, error) {
+	var thread ProposalThread
+	err := a.db.QueryRow("SELECT chainName, proposalId, channelId, threadTs, title, votingEndTime, status, createdAt FROM proposal_threads WHERE chainName = ? AND proposalId = ?",
+		chainName, proposalID).Scan(&thread.ChainName, &thread.ProposalID, &thread.ChannelID, &thread.ThreadTS,
+		&thread.Title, &thread.VotingEndTime, &thread.Status, &thread.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ProposalThread{}, false, nil
+	}
+	if err != nil {
+		return ProposalThread{}, false, err
+	}
+
+	return thread, true, nil
+}
+
+// Updates the status of the thread of the proposal
+func (a *Sqlitedb) SetProposalThreadStatus(chainName, proposalID, status string) error {
+	stmt, err := a.db.Prepare("UPDATE proposal_threads SET status = ? WHERE chainName = ? AND proposalId = ?")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(status, chainName, proposalID)
+	return err
+}
+
+// Gets the votes of the validators on the proposal from the vote logs
+func (a *Sqlitedb) GetProposalVotes(chainName, proposalID string) ([]ProposalVote, error) {
+	rows, err := a.db.Query("SELECT validatorAddress, voteOption, txHash, confirmed FROM logs WHERE chainName = ? AND proposalId = ? ORDER BY validatorAddress",
+		chainName, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []ProposalVote
+	for rows.Next() {
+		var data ProposalVote
+		if err := rows.Scan(&data.ValidatorAddress, &data.VoteOption, &data.TxHash, &data.Confirmed); err != nil {
+			return votes, err
+		}
+		votes = append(votes, data)
+	}
+
+	return votes, rows.Err()
+}
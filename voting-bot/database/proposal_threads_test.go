@@ -0,0 +1,50 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposalThreads(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, found, err := sqlitedb.GetProposalThread("chain1", "1")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	thread := ProposalThread{ChainName: "chain1", ProposalID: "1", ChannelID: "C1", ThreadTS: "100.1", Title: "Upgrade", Status: ThreadVotingPeriod}
+	added, err := sqlitedb.AddProposalThread(thread)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// the thread of the first alert is kept
+	thread.ThreadTS = "200.1"
+	added, err = sqlitedb.AddProposalThread(thread)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	stored, found, err := sqlitedb.GetProposalThread("chain1", "1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "100.1", stored.ThreadTS)
+	assert.Equal(t, "C1", stored.ChannelID)
+	assert.Equal(t, ThreadVotingPeriod, stored.Status)
+
+	assert.NoError(t, sqlitedb.SetProposalThreadStatus("chain1", "1", OutcomePassed))
+	stored, _, err = sqlitedb.GetProposalThread("chain1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, OutcomePassed, stored.Status)
+
+	assert.NoError(t, sqlitedb.AddLog("chain1", "val2", "Upgrade", "1", ""))
+	assert.NoError(t, sqlitedb.AddLog("chain1", "val1", "Upgrade", "1", ""))
+	assert.NoError(t, sqlitedb.AddLog("chain2", "val3", "Other", "1", ""))
+	assert.NoError(t, sqlitedb.ConfirmVoteLog("chain1", "val1", "1", "VOTE_OPTION_YES", "ABC"))
+
+	votes, err := sqlitedb.GetProposalVotes("chain1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ProposalVote{
+		{ValidatorAddress: "val1", VoteOption: "VOTE_OPTION_YES", TxHash: "ABC", Confirmed: true},
+		{ValidatorAddress: "val2"},
+	}, votes)
+}
@@ -0,0 +1,25 @@
+package database
+
+import "time"
+
+// Checks if the deadline reminder of the threshold was already handled for
+// the proposal
+func (a *Sqlitedb) HasProposalReminder(chainName, proposalID string, threshold time.Duration) (bool, error) {
+	var exists bool
+	err := a.db.QueryRow("SELECT EXISTS(SELECT 1 FROM proposal_reminders WHERE chainName = ? AND proposalId = ? AND threshold = ?)",
+		chainName, proposalID, int64(threshold.Seconds())).Scan(&exists)
+	return exists, err
+}
+
+// Marks the deadline reminder of the threshold as handled for the proposal
+func (a *Sqlitedb) AddProposalReminder(chainName, proposalID string, threshold time.Duration) error {
+	stmt, err := a.db.Prepare("INSERT OR IGNORE INTO proposal_reminders(chainName, proposalId, threshold, date) values(?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(chainName, proposalID, int64(threshold.Seconds()), time.Now().UTC().Unix())
+	return err
+}
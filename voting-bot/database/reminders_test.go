@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProposalReminders(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	sent, err := sqlitedb.HasProposalReminder("chain1", "1", 24*time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	assert.NoError(t, sqlitedb.AddProposalReminder("chain1", "1", 24*time.Hour))
+	// a reminder is only stored once
+	assert.NoError(t, sqlitedb.AddProposalReminder("chain1", "1", 24*time.Hour))
+
+	sent, err = sqlitedb.HasProposalReminder("chain1", "1", 24*time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	sent, err = sqlitedb.HasProposalReminder("chain1", "1", 6*time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	sent, err = sqlitedb.HasProposalReminder("chain2", "1", 24*time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	// Actions taken on a matched voting rule
+	RuleActionVoted      = "voted"
+	RuleActionVoteFailed = "vote failed"
+	RuleActionSuggested  = "suggested"
+)
+
+// RuleDecision records a voting rule which fired on a proposal and the
+// action taken for the validator
+type RuleDecision struct {
+	Date             int64
+	ChainName        string
+	ValidatorAddress string
+	ProposalID       string
+	RuleName         string
+	VoteOption       string
+	Rationale        string
+	Action           string
+	Details          string
+}
+
+// Stores the decision of a voting rule
+func (a *Sqlitedb) AddRuleDecision(decision RuleDecision) error {
+	stmt, err := a.db.Prepare("INSERT INTO rule_decisions(date, chainName, validatorAddress, proposalId, ruleName, voteOption, rationale, action, details) values(?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(time.Now().UTC().Unix(), decision.ChainName, decision.ValidatorAddress, decision.ProposalID,
+		decision.RuleName, decision.VoteOption, decision.Rationale, decision.Action, decision.Details)
+	return err
+}
+
+// Gets the latest decision of a voting rule for the validator on the proposal
+func (a *Sqlitedb) GetRuleDecision(chainName, validatorAddress, proposalID string) (RuleDecision, bool, error) {
+	rows, err := a.db.Query("SELECT date, chainName, validatorAddress, proposalId, ruleName, voteOption, rationale, action, details FROM rule_decisions WHERE chainName = ? AND validatorAddress = ? AND proposalId = ? ORDER BY rowid DESC LIMIT 1",
+		chainName, validatorAddress, proposalID)
+	if err != nil {
+		return RuleDecision{}, false, err
+	}
+	defer rows.Close()
+
+	decisions, err := scanRuleDecisions(rows)
+	if err != nil || len(decisions) == 0 {
+		return RuleDecision{}, false, err
+	}
+
+	return decisions[0], true, nil
+}
+
+// Gets all decisions of voting rules on the chain
+func (a *Sqlitedb) GetRuleDecisions(chainName string) ([]RuleDecision, error) {
+	rows, err := a.db.Query("SELECT date, chainName, validatorAddress, proposalId, ruleName, voteOption, rationale, action, details FROM rule_decisions WHERE chainName = ? ORDER BY rowid", chainName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRuleDecisions(rows)
+}
+
+func scanRuleDecisions(rows *sql.Rows) ([]RuleDecision, error) {
+	var decisions []RuleDecision
+	for rows.Next() {
+		var data RuleDecision
+		if err := rows.Scan(&data.Date, &data.ChainName, &data.ValidatorAddress, &data.ProposalID, &data.RuleName,
+			&data.VoteOption, &data.Rationale, &data.Action, &data.Details); err != nil {
+			return decisions, err
+		}
+		decisions = append(decisions, data)
+	}
+
+	return decisions, rows.Err()
+}
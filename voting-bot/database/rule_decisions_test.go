@@ -0,0 +1,48 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleDecisions(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, found, err := sqlitedb.GetRuleDecision("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	suggested := RuleDecision{
+		ChainName:        "chain1",
+		ValidatorAddress: "val1",
+		ProposalID:       "1",
+		RuleName:         "ibc-client-recovery",
+		VoteOption:       "yes",
+		Rationale:        "routine",
+		Action:           RuleActionSuggested,
+	}
+	assert.NoError(t, sqlitedb.AddRuleDecision(suggested))
+
+	voted := suggested
+	voted.Action = RuleActionVoted
+	assert.NoError(t, sqlitedb.AddRuleDecision(voted))
+
+	other := suggested
+	other.ChainName = "chain2"
+	assert.NoError(t, sqlitedb.AddRuleDecision(other))
+
+	// the latest decision is returned
+	decision, found, err := sqlitedb.GetRuleDecision("chain1", "val1", "1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	voted.Date = time.Now().UTC().Unix()
+	assert.Equal(t, voted, decision)
+
+	decisions, err := sqlitedb.GetRuleDecisions("chain1")
+	assert.NoError(t, err)
+	assert.Len(t, decisions, 2)
+	assert.Equal(t, RuleActionSuggested, decisions[0].Action)
+	assert.Equal(t, RuleActionVoted, decisions[1].Action)
+}
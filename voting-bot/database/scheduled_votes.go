@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// Scheduled vote statuses
+	ScheduledVotePending   = "pending"
+	ScheduledVoteSubmitted = "submitted"
+	ScheduledVoteCancelled = "cancelled"
+	ScheduledVoteSkipped   = "skipped"
+)
+
+// ScheduledVote is a vote which is queued to be executed at a later time
+type ScheduledVote struct {
+	ID int64
+	// Target is the chain name or the validator address to vote for
+	Target      string
+	ProposalID  string
+	VoteType    string
+	VoteOption  string
+	GasPrices   string
+	Memo        string
+	Metadata    string
+	ScheduledBy string
+	// ExecuteAt is the unix time at which the vote is executed
+	ExecuteAt int64
+	// BeforeEnd is the offset from the end of the voting period the vote was
+	// scheduled with, 0 if it was scheduled at a fixed time
+	BeforeEnd time.Duration
+	// Fallback votes are scheduled by the fallback vote policy of the chain
+	// and are skipped if the validator has voted in the meantime
+	Fallback bool
+	// VoteRequestID is the vote request approving the vote if approvals are
+	// required, 0 otherwise
+	VoteRequestID int64
+	Status        string
+	CreatedAt     int64
+}
+
+const scheduledVoteColumns = "id, target, proposalId, voteType, voteOption, gasPrices, memo, metadata, scheduledBy, executeAt, beforeEnd, fallback, voteRequestId, status, createdAt"
+
+// Stores a vote to be executed at the scheduled time
+func (a *Sqlitedb) AddScheduledVote(vote ScheduledVote) (int64, error) {
+	stmt, err := a.db.Prepare("INSERT INTO scheduled_votes(target, proposalId, voteType, voteOption, gasPrices, memo, metadata, scheduledBy, executeAt, beforeEnd, fallback, voteRequestId, status, createdAt) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return 0, err
+	}
+
+	defer stmt.Close()
+
+	res, err := stmt.Exec(vote.Target, vote.ProposalID, vote.VoteType, vote.VoteOption, vote.GasPrices, vote.Memo, vote.Metadata,
+		vote.ScheduledBy, vote.ExecuteAt, int64(vote.BeforeEnd/time.Second), vote.Fallback, vote.VoteRequestID, ScheduledVotePending, time.Now().UTC().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// Gets a scheduled vote
+func (a *Sqlitedb) GetScheduledVote(id int64) (ScheduledVote, error) {
+	vote, err := scanScheduledVote(a.db.QueryRow("SELECT "+scheduledVoteColumns+" FROM scheduled_votes WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return ScheduledVote{}, fmt.Errorf("scheduled vote %d does not exist", id)
+	}
+
+	return vote, err
+}
+
+// Gets all pending scheduled votes, ordered by the time they are executed at
+func (a *Sqlitedb) GetPendingScheduledVotes() ([]ScheduledVote, error) {
+	return a.queryScheduledVotes("SELECT "+scheduledVoteColumns+" FROM scheduled_votes WHERE status = ? ORDER BY executeAt", ScheduledVotePending)
+}
+
+// Gets the pending scheduled votes whose time has come
+func (a *Sqlitedb) GetDueScheduledVotes(now time.Time) ([]ScheduledVote, error) {
+	return a.queryScheduledVotes("SELECT "+scheduledVoteColumns+" FROM scheduled_votes WHERE status = ? AND executeAt <= ? ORDER BY executeAt", ScheduledVotePending, now.Unix())
+}
+
+// Gets the scheduled vote approved by the vote request, found is false if the
+// request is not for a scheduled vote
+func (a *Sqlitedb) GetScheduledVoteByRequest(requestID int64) (ScheduledVote, bool, error) {
+	vote, err := scanScheduledVote(a.db.QueryRow("SELECT "+scheduledVoteColumns+" FROM scheduled_votes WHERE voteRequestId = ?", requestID))
+	if err == sql.ErrNoRows {
+		return ScheduledVote{}, false, nil
+	}
+	if err != nil {
+		return ScheduledVote{}, false, err
+	}
+
+	return vote, true, nil
+}
+
+// Checks if a fallback vote was ever scheduled for the target on the
+// proposal, including fallback votes which were cancelled
+func (a *Sqlitedb) HasFallbackVote(target, proposalID string) (bool, error) {
+	var exists bool
+	err := a.db.QueryRow("SELECT EXISTS(SELECT 1 FROM scheduled_votes WHERE target = ? AND proposalId = ? AND fallback = 1)", target, proposalID).Scan(&exists)
+	return exists, err
+}
+
+// Moves the scheduled vote from one status to another. It returns false if
+// the vote is no longer in the from status, so that a vote is only executed
+// or cancelled once.
+func (a *Sqlitedb) UpdateScheduledVoteStatus(id int64, from, to string) (bool, error) {
+	res, err := a.db.Exec("UPDATE scheduled_votes SET status = ? WHERE id = ? AND status = ?", to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated == 1, nil
+}
+
+func (a *Sqlitedb) queryScheduledVotes(query string, args ...interface{}) ([]ScheduledVote, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []ScheduledVote
+	for rows.Next() {
+		vote, err := scanScheduledVote(rows)
+		if err != nil {
+			return votes, err
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, rows.Err()
+}
+
+func scanScheduledVote(row rowScanner) (ScheduledVote, error) {
+	var vote ScheduledVote
+	var beforeEnd int64
+	err := row.Scan(&vote.ID, &vote.Target, &vote.ProposalID, &vote.VoteType, &vote.VoteOption, &vote.GasPrices, &vote.Memo,
+		&vote.Metadata, &vote.ScheduledBy, &vote.ExecuteAt, &beforeEnd, &vote.Fallback, &vote.VoteRequestID, &vote.Status, &vote.CreatedAt)
+	vote.BeforeEnd = time.Duration(beforeEnd) * time.Second
+	return vote, err
+}
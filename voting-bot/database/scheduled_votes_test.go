@@ -0,0 +1,110 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledVotes(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	now := time.Now().UTC()
+	dueID, err := sqlitedb.AddScheduledVote(ScheduledVote{
+		Target:      "cosmoshub",
+		ProposalID:  "12",
+		VoteType:    VoteTypeSingle,
+		VoteOption:  "yes",
+		GasPrices:   "0.25uatom",
+		ScheduledBy: "U1",
+		ExecuteAt:   now.Add(-time.Minute).Unix(),
+		BeforeEnd:   6 * time.Hour,
+	})
+	assert.NoError(t, err)
+
+	laterID, err := sqlitedb.AddScheduledVote(ScheduledVote{
+		Target:        "osmosis",
+		ProposalID:    "3",
+		VoteType:      VoteTypeWeighted,
+		VoteOption:    "yes=0.5,abstain=0.5",
+		ScheduledBy:   "U2",
+		ExecuteAt:     now.Add(time.Hour).Unix(),
+		VoteRequestID: 7,
+	})
+	assert.NoError(t, err)
+
+	vote, err := sqlitedb.GetScheduledVote(dueID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cosmoshub", vote.Target)
+	assert.Equal(t, 6*time.Hour, vote.BeforeEnd)
+	assert.Equal(t, ScheduledVotePending, vote.Status)
+
+	vote, found, err := sqlitedb.GetScheduledVoteByRequest(7)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, laterID, vote.ID)
+
+	_, found, err = sqlitedb.GetScheduledVoteByRequest(8)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	pending, err := sqlitedb.GetPendingScheduledVotes()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, dueID, pending[0].ID)
+	assert.Equal(t, laterID, pending[1].ID)
+
+	due, err := sqlitedb.GetDueScheduledVotes(now)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, dueID, due[0].ID)
+
+	updated, err := sqlitedb.UpdateScheduledVoteStatus(dueID, ScheduledVotePending, ScheduledVoteSubmitted)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	// cancelling an executed vote has no effect
+	updated, err = sqlitedb.UpdateScheduledVoteStatus(dueID, ScheduledVotePending, ScheduledVoteCancelled)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	due, err = sqlitedb.GetDueScheduledVotes(now.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, laterID, due[0].ID)
+
+	_, err = sqlitedb.GetScheduledVote(laterID + 1)
+	assert.Error(t, err)
+}
+
+func TestFallbackVotes(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, err := sqlitedb.AddScheduledVote(ScheduledVote{Target: "cosmosvaloper1...", ProposalID: "12", VoteOption: "yes"})
+	assert.NoError(t, err)
+
+	// votes scheduled by users are not fallback votes
+	exists, err := sqlitedb.HasFallbackVote("cosmosvaloper1...", "12")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	id, err := sqlitedb.AddScheduledVote(ScheduledVote{Target: "cosmosvaloper1...", ProposalID: "12", VoteOption: "abstain", Fallback: true})
+	assert.NoError(t, err)
+
+	vote, err := sqlitedb.GetScheduledVote(id)
+	assert.NoError(t, err)
+	assert.True(t, vote.Fallback)
+
+	// cancelled fallback votes are not scheduled again
+	_, err = sqlitedb.UpdateScheduledVoteStatus(id, ScheduledVotePending, ScheduledVoteCancelled)
+	assert.NoError(t, err)
+
+	exists, err = sqlitedb.HasFallbackVote("cosmosvaloper1...", "12")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = sqlitedb.HasFallbackVote("cosmosvaloper1...", "13")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -18,16 +18,20 @@ type (
 	}
 
 	voteLogs struct {
-		Date          int64  `json:"date"`
-		ChainName     string `json:"chainName"`
-		ProposalTitle string `json:"proposalTitle"`
-		ProposalID    string `json:"proposalID"`
-		VoteOption    string `json:"voteOption"`
+		Date             int64  `json:"date"`
+		ChainName        string `json:"chainName"`
+		ValidatorAddress string `json:"validatorAddress"`
+		ProposalTitle    string `json:"proposalTitle"`
+		ProposalID       string `json:"proposalID"`
+		VoteOption       string `json:"voteOption"`
+		TxHash           string `json:"txHash"`
+		Confirmed        bool   `json:"confirmed"`
 	}
 
 	Proposal struct {
 		ProposalID string `json:"proposalID"`
 		Title      string `json:"title"`
+		Validator  string `json:"validator"`
 		VoteOption string `json:"vote_option"`
 	}
 
@@ -60,29 +64,217 @@ func (a *Sqlitedb) Close() error {
 
 // Creates all the required tables in database
 func (a *Sqlitedb) InitializeTables() error {
-	_, err := a.db.Exec("CREATE TABLE IF NOT EXISTS validators (chainName VARCHAR PRIMARY KEY, address VARCHAR )")
+	// validators created before multiple validators per chain were supported
+	// use chainName as primary key and need to be migrated first
+	if err := a.migrateValidators(); err != nil {
+		return err
+	}
+
+	_, err := a.db.Exec("CREATE TABLE IF NOT EXISTS validators (chainName VARCHAR, address VARCHAR, PRIMARY KEY (chainName, address))")
 	if err != nil {
 		return err
 	}
 
-	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR, proposalTitle VARCHAR)")
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR, proposalTitle VARCHAR, validatorAddress VARCHAR DEFAULT '', txHash VARCHAR DEFAULT '', confirmed BOOLEAN DEFAULT 0)")
 	if err != nil {
 		return err
 	}
 
-	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS keys (chainName VARCHAR, keyName VARCHAR, granteeAddress VARCHAR, type VARCHAR, authzStatus VARCHAR DEFAULT 'false', PRIMARY KEY (chainName, type))")
+	if err := a.addColumnIfMissing("logs", "txHash", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+
+	if err := a.addColumnIfMissing("logs", "confirmed", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS keys (chainName VARCHAR, keyName VARCHAR, granteeAddress VARCHAR, type VARCHAR, authzStatus VARCHAR DEFAULT 'false', balance VARCHAR DEFAULT '', balanceUpdatedAt INTEGER DEFAULT 0, PRIMARY KEY (chainName, type))")
 	if err != nil {
 		return err
 	}
 
+	if err := a.addColumnIfMissing("keys", "balance", "VARCHAR DEFAULT ''"); err != nil {
+		return err
+	}
+
+	if err := a.addColumnIfMissing("keys", "balanceUpdatedAt", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
 	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS income (chainId VARCHAR, denom VARCHAR, valAddress VARCHAR, rewards VARCHAR, commission VARCHAR, date VARCHAR)")
 	if err != nil {
 		return err
 	}
 
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS vote_requests (id INTEGER PRIMARY KEY AUTOINCREMENT, target VARCHAR, proposalId VARCHAR, voteType VARCHAR, voteOption VARCHAR, gasPrices VARCHAR, memo VARCHAR, metadata VARCHAR, requestedBy VARCHAR, status VARCHAR, createdAt INTEGER, expiresAt INTEGER)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS vote_approvals (requestId INTEGER, userId VARCHAR, date INTEGER, PRIMARY KEY (requestId, userId))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS vote_audit (date INTEGER, requestId INTEGER, userId VARCHAR, action VARCHAR, details VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS scheduled_votes (id INTEGER PRIMARY KEY AUTOINCREMENT, target VARCHAR, proposalId VARCHAR, voteType VARCHAR, voteOption VARCHAR, gasPrices VARCHAR, memo VARCHAR, metadata VARCHAR, scheduledBy VARCHAR, executeAt INTEGER, beforeEnd INTEGER DEFAULT 0, fallback BOOLEAN DEFAULT 0, voteRequestId INTEGER DEFAULT 0, status VARCHAR, createdAt INTEGER)")
+	if err != nil {
+		return err
+	}
+
+	if err := a.addColumnIfMissing("scheduled_votes", "fallback", "BOOLEAN DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := a.addColumnIfMissing("scheduled_votes", "voteRequestId", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS rule_decisions (date INTEGER, chainName VARCHAR, validatorAddress VARCHAR, proposalId VARCHAR, ruleName VARCHAR, voteOption VARCHAR, rationale VARCHAR, action VARCHAR, details VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS chain_capabilities (chainName VARCHAR PRIMARY KEY, govV1 BOOLEAN, govV1beta1 BOOLEAN, authz BOOLEAN, sdkVersion VARCHAR, checkedAt INTEGER)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS proposal_alerts (chainName VARCHAR, validatorAddress VARCHAR, proposalId VARCHAR, firstSeen INTEGER, lastAlerted INTEGER DEFAULT 0, PRIMARY KEY (chainName, validatorAddress, proposalId))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS proposal_reminders (chainName VARCHAR, proposalId VARCHAR, threshold INTEGER, date INTEGER, PRIMARY KEY (chainName, proposalId, threshold))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS upgrades (chainName VARCHAR, name VARCHAR, height INTEGER, info VARCHAR, proposalId VARCHAR, estimatedTime INTEGER, status VARCHAR, updatedAt INTEGER, PRIMARY KEY (chainName, name))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS upgrade_reminders (chainName VARCHAR, name VARCHAR, threshold INTEGER, date INTEGER, PRIMARY KEY (chainName, name, threshold))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS proposal_outcomes (chainName VARCHAR, proposalId VARCHAR, status VARCHAR, outcome VARCHAR, yes VARCHAR, abstain VARCHAR, no VARCHAR, noWithVeto VARCHAR, votingEndTime VARCHAR, recordedAt INTEGER, PRIMARY KEY (chainName, proposalId))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS proposal_threads (chainName VARCHAR, proposalId VARCHAR, channelId VARCHAR, threadTs VARCHAR, title VARCHAR, votingEndTime VARCHAR, status VARCHAR, createdAt INTEGER, PRIMARY KEY (chainName, proposalId))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS audit_log (id INTEGER PRIMARY KEY AUTOINCREMENT, date INTEGER, userId VARCHAR, command VARCHAR, chainName VARCHAR, params VARCHAR, result VARCHAR, txHash VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS active_proposals (chainName VARCHAR, proposalId VARCHAR, title VARCHAR, votingEndTime VARCHAR, updatedAt INTEGER, PRIMARY KEY (chainName, proposalId))")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS job_runs (name VARCHAR PRIMARY KEY, startedAt INTEGER, finishedAt INTEGER, status VARCHAR, error VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("CREATE TABLE IF NOT EXISTS app_home_users (userId VARCHAR PRIMARY KEY, openedAt INTEGER)")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// migrateValidators moves the validators of an existing database into a table
+// keyed by chain name and address, so a chain can have several validators.
+// The vote logs, which were kept per chain, are assigned to the validator
+// that was registered for the chain.
+func (a *Sqlitedb) migrateValidators() error {
+	var pkColumns int
+	err := a.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('validators') WHERE pk > 0").Scan(&pkColumns)
+	if err != nil {
+		return err
+	}
+
+	// table does not exist yet or is already migrated
+	if pkColumns != 1 {
+		return nil
+	}
+
+	log.Println("Migrating validators table to support multiple validators per chain...")
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"CREATE TABLE validators_new (chainName VARCHAR, address VARCHAR, PRIMARY KEY (chainName, address))",
+		"INSERT INTO validators_new(chainName, address) SELECT chainName, address FROM validators WHERE address IS NOT NULL",
+	}
+
+	var hasLogs bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'logs')").Scan(&hasLogs)
+	if err != nil {
+		return err
+	}
+
+	if hasLogs {
+		var hasValidatorColumn bool
+		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('logs') WHERE name = 'validatorAddress')").Scan(&hasValidatorColumn)
+		if err != nil {
+			return err
+		}
+
+		if !hasValidatorColumn {
+			statements = append(statements, "ALTER TABLE logs ADD COLUMN validatorAddress VARCHAR DEFAULT ''")
+		}
+
+		statements = append(statements, "UPDATE logs SET validatorAddress = COALESCE((SELECT address FROM validators WHERE validators.chainName = logs.chainName), '') WHERE validatorAddress IS NULL OR validatorAddress = ''")
+	}
+
+	statements = append(statements,
+		"DROP TABLE validators",
+		"ALTER TABLE validators_new RENAME TO validators",
+	)
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to migrate validators: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// addColumnIfMissing adds the column to a table created by an older version
+func (a *Sqlitedb) addColumnIfMissing(table, column, definition string) error {
+	var exists bool
+	err := a.db.QueryRow(fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM pragma_table_info('%s') WHERE name = ?)", table), column).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	_, err = a.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
 // Stores validator information
 func (s *Sqlitedb) AddValidator(name, address string) error {
 	stmt, err := s.db.Prepare("INSERT INTO validators(chainName, address) values(?,?)")
@@ -110,28 +302,41 @@ func (s *Sqlitedb) RemoveValidator(address string) error {
 }
 
 // Update vote logs information
-func (a *Sqlitedb) UpdateVoteLog(chainName, proposalID, voteOption string) error {
-	stmt, err := a.db.Prepare("UPDATE logs SET voteOption = ? WHERE chainName = ? AND proposalID = ?")
+func (a *Sqlitedb) UpdateVoteLog(chainName, validatorAddress, proposalID, voteOption string) error {
+	stmt, err := a.db.Prepare("UPDATE logs SET voteOption = ? WHERE chainName = ? AND validatorAddress = ? AND proposalID = ?")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(voteOption, chainName, validatorAddress, proposalID)
+	return err
+}
+
+// Marks the vote as confirmed on chain and stores the hash of the vote transaction
+func (a *Sqlitedb) ConfirmVoteLog(chainName, validatorAddress, proposalID, voteOption, txHash string) error {
+	stmt, err := a.db.Prepare("UPDATE logs SET voteOption = ?, txHash = ?, confirmed = 1 WHERE chainName = ? AND validatorAddress = ? AND proposalID = ?")
 	if err != nil {
 		return err
 	}
 
 	defer stmt.Close()
 
-	_, err = stmt.Exec(voteOption, chainName, proposalID)
+	_, err = stmt.Exec(voteOption, txHash, chainName, validatorAddress, proposalID)
 	return err
 }
 
 // Adds vote logs information
-func (s *Sqlitedb) AddLog(chainName, proposalTitle, proposalID, voteOption string) error {
-	stmt, err := s.db.Prepare("SELECT EXISTS(SELECT 1 FROM logs WHERE chainName = ? AND proposalID = ?)")
+func (s *Sqlitedb) AddLog(chainName, validatorAddress, proposalTitle, proposalID, voteOption string) error {
+	stmt, err := s.db.Prepare("SELECT EXISTS(SELECT 1 FROM logs WHERE chainName = ? AND validatorAddress = ? AND proposalID = ?)")
 	if err != nil {
 		log.Println(err)
 		return err
 	}
 
 	var exists bool
-	err = stmt.QueryRow(chainName, proposalID).Scan(&exists)
+	err = stmt.QueryRow(chainName, validatorAddress, proposalID).Scan(&exists)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -140,25 +345,25 @@ func (s *Sqlitedb) AddLog(chainName, proposalTitle, proposalID, voteOption strin
 	stmt.Close()
 
 	if !exists {
-		stmt, err = s.db.Prepare("INSERT INTO logs(date, chainName, proposalTitle, proposalID, voteOption) values(?,?,?,?,?)")
+		stmt, err = s.db.Prepare("INSERT INTO logs(date, chainName, validatorAddress, proposalTitle, proposalID, voteOption) values(?,?,?,?,?,?)")
 		if err != nil {
 			return err
 		}
 
 		defer stmt.Close()
 
-		_, err = stmt.Exec(time.Now().UTC().Unix(), chainName, proposalTitle, proposalID, voteOption)
+		_, err = stmt.Exec(time.Now().UTC().Unix(), chainName, validatorAddress, proposalTitle, proposalID, voteOption)
 		return err
 	} else {
 		if voteOption != "" {
-			stmt, err = s.db.Prepare("UPDATE logs SET date=?, proposalTitle=?, voteOption=? WHERE chainName=? AND proposalID=?")
+			stmt, err = s.db.Prepare("UPDATE logs SET date=?, proposalTitle=?, voteOption=? WHERE chainName=? AND validatorAddress=? AND proposalID=?")
 			if err != nil {
 				return err
 			}
 
 			defer stmt.Close()
 
-			_, err = stmt.Exec(time.Now().UTC().Unix(), proposalTitle, voteOption, chainName, proposalID)
+			_, err = stmt.Exec(time.Now().UTC().Unix(), proposalTitle, voteOption, chainName, validatorAddress, proposalID)
 			return err
 		}
 		return nil
@@ -238,20 +443,40 @@ func (s *Sqlitedb) GetValidatorAddress() (ValidatorAddress []string, err error)
 	return ValidatorAddress, nil
 }
 
-func (a *Sqlitedb) GetChainValidator(ChainName string) (string, error) {
-	var addr string
-	stmt, err := a.db.Prepare("SELECT address FROM validators WHERE chainName=?")
+// Gets the addresses of all validators registered for the chain
+func (a *Sqlitedb) GetChainValidators(chainName string) ([]string, error) {
+	rows, err := a.db.Query("SELECT address FROM validators WHERE chainName=?", chainName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer stmt.Close()
+	defer rows.Close()
 
-	err = stmt.QueryRow(ChainName).Scan(&addr)
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// Gets the validator registered with the given address
+func (a *Sqlitedb) GetValidator(address string) (Validator, error) {
+	var validator Validator
+	stmt, err := a.db.Prepare("SELECT chainName, address FROM validators WHERE address=?")
 	if err != nil {
-		return "", err
+		return validator, err
 	}
+	defer stmt.Close()
 
-	return addr, nil
+	err = stmt.QueryRow(address).Scan(&validator.ChainName, &validator.Address)
+	return validator, err
 }
 
 // Gets required data regarding votes
@@ -274,7 +499,7 @@ func (a *Sqlitedb) GetVoteLogs(chainName, startDate, endDate string) ([]voteLogs
 	if start.Unix() >= end {
 		return nil, fmt.Errorf("start date is not valid as it is greater than end date")
 	}
-	query := "SELECT date, chainName, proposalTitle, proposalId, voteOption FROM logs WHERE chainName = ? AND date BETWEEN ? AND ? "
+	query := "SELECT date, chainName, validatorAddress, proposalTitle, proposalId, voteOption, txHash, confirmed FROM logs WHERE chainName = ? AND date BETWEEN ? AND ? "
 	rows, err := a.db.Query(query, chainName, start.Unix(), end)
 	if err != nil {
 		return []voteLogs{}, err
@@ -284,7 +509,7 @@ func (a *Sqlitedb) GetVoteLogs(chainName, startDate, endDate string) ([]voteLogs
 	var k []voteLogs
 	for rows.Next() {
 		var data voteLogs
-		if err := rows.Scan(&data.Date, &data.ChainName, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
+		if err := rows.Scan(&data.Date, &data.ChainName, &data.ValidatorAddress, &data.ProposalTitle, &data.ProposalID, &data.VoteOption, &data.TxHash, &data.Confirmed); err != nil {
 			return k, err
 		}
 		k = append(k, data)
@@ -348,7 +573,7 @@ func (a *Sqlitedb) GetProposals(chainName, start, end string) (map[string][]Prop
 	obj := make(map[string][]Proposal)
 
 	if start != "" && chainName != "" {
-		query := "SELECT date,chainName, proposalTitle, proposalId, voteOption FROM logs WHERE chainName = ? AND date BETWEEN ? AND ?"
+		query := "SELECT date, chainName, validatorAddress, proposalTitle, proposalId, voteOption FROM logs WHERE chainName = ? AND date BETWEEN ? AND ?"
 		rows, err := a.db.Query(query, chainName, start, end)
 		if err != nil {
 			return map[string][]Proposal{}, err
@@ -358,12 +583,13 @@ func (a *Sqlitedb) GetProposals(chainName, start, end string) (map[string][]Prop
 
 		for rows.Next() {
 			var data voteLogs
-			if err := rows.Scan(&data.Date, &data.ChainName, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
+			if err := rows.Scan(&data.Date, &data.ChainName, &data.ValidatorAddress, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
 				return map[string][]Proposal{}, err
 			}
 			proposal := Proposal{
 				ProposalID: data.ProposalID,
 				Title:      data.ProposalTitle,
+				Validator:  data.ValidatorAddress,
 				VoteOption: data.VoteOption,
 			}
 			if _, ok := obj[data.ChainName]; ok {
@@ -376,7 +602,7 @@ func (a *Sqlitedb) GetProposals(chainName, start, end string) (map[string][]Prop
 			return map[string][]Proposal{}, err
 		}
 	} else {
-		query := "SELECT date, chainName, proposalTitle, proposalId, voteOption FROM logs WHERE date BETWEEN ? AND ? "
+		query := "SELECT date, chainName, validatorAddress, proposalTitle, proposalId, voteOption FROM logs WHERE date BETWEEN ? AND ? "
 		rows, err := a.db.Query(query, start, end)
 		if err != nil {
 			return map[string][]Proposal{}, err
@@ -385,12 +611,13 @@ func (a *Sqlitedb) GetProposals(chainName, start, end string) (map[string][]Prop
 
 		for rows.Next() {
 			var data voteLogs
-			if err := rows.Scan(&data.Date, &data.ChainName, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
+			if err := rows.Scan(&data.Date, &data.ChainName, &data.ValidatorAddress, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
 				return map[string][]Proposal{}, err
 			}
 			proposal := Proposal{
 				ProposalID: data.ProposalID,
 				Title:      data.ProposalTitle,
+				Validator:  data.ValidatorAddress,
 				VoteOption: data.VoteOption,
 			}
 			if _, ok := obj[data.ChainName]; ok {
@@ -413,7 +640,7 @@ func (a *Sqlitedb) GetProposalsForAllNetworks(start, end string) (map[string][]P
 	obj := make(map[string][]Proposal)
 
 	if start != "" && end != "" {
-		query := "SELECT date,chainName, proposalTitle, proposalId, voteOption FROM logs WHERE date BETWEEN ? AND ?"
+		query := "SELECT date, chainName, validatorAddress, proposalTitle, proposalId, voteOption FROM logs WHERE date BETWEEN ? AND ?"
 		rows, err := a.db.Query(query, start, end)
 		if err != nil {
 			return map[string][]Proposal{}, err
@@ -423,12 +650,13 @@ func (a *Sqlitedb) GetProposalsForAllNetworks(start, end string) (map[string][]P
 
 		for rows.Next() {
 			var data voteLogs
-			if err := rows.Scan(&data.Date, &data.ChainName, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
+			if err := rows.Scan(&data.Date, &data.ChainName, &data.ValidatorAddress, &data.ProposalTitle, &data.ProposalID, &data.VoteOption); err != nil {
 				return map[string][]Proposal{}, err
 			}
 			proposal := Proposal{
 				ProposalID: data.ProposalID,
 				Title:      data.ProposalTitle,
+				Validator:  data.ValidatorAddress,
 				VoteOption: data.VoteOption,
 			}
 			if _, ok := obj[data.ChainName]; ok {
@@ -446,15 +674,15 @@ func (a *Sqlitedb) GetProposalsForAllNetworks(start, end string) (map[string][]P
 	return obj, nil
 }
 
-func (a *Sqlitedb) IsIncomeRecordExist(chainId, date string) (bool, error) {
-	stmt, err := a.db.Prepare("SELECT EXISTS(SELECT 1 FROM income WHERE chainId = ? AND date = ?)")
+func (a *Sqlitedb) IsIncomeRecordExist(chainId, valAddr, date string) (bool, error) {
+	stmt, err := a.db.Prepare("SELECT EXISTS(SELECT 1 FROM income WHERE chainId = ? AND valAddress = ? AND date = ?)")
 	if err != nil {
 		return false, err
 	}
 	defer stmt.Close()
 
 	var exists bool
-	err = stmt.QueryRow(chainId, date).Scan(&exists)
+	err = stmt.QueryRow(chainId, valAddr, date).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
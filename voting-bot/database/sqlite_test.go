@@ -9,13 +9,29 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// newTestDB returns an in-memory database with all tables
+func newTestDB(t *testing.T) *Sqlitedb {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	sqlitedb := &Sqlitedb{db: db}
+	if err := sqlitedb.InitializeTables(); err != nil {
+		t.Fatalf("Failed to create test tables: %v", err)
+	}
+
+	return sqlitedb
+}
+
 func TestValidators(t *testing.T) {
 	db, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
 	defer db.Close()
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS validators (chainName VARCHAR PRIMARY KEY, address VARCHAR )")
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS validators (chainName VARCHAR, address VARCHAR, PRIMARY KEY (chainName, address))")
 	if err != nil {
 		fmt.Println(err)
 		t.Fatalf("Failed to create test table: %v", err)
@@ -38,10 +54,10 @@ func TestValidators(t *testing.T) {
 
 	expectedAddress := []string{"cosmos1..."}
 	assert.Equal(t, expectedAddress, log)
-	val, err := sqlitedb.GetChainValidator("chain1")
+	val, err := sqlitedb.GetChainValidators("chain1")
 	assert.NoError(t, err)
 
-	expectedVal := "cosmos1..."
+	expectedVal := []string{"cosmos1..."}
 	assert.Equal(t, expectedVal, val)
 
 	logs, err := sqlitedb.GetValidators()
@@ -53,7 +69,21 @@ func TestValidators(t *testing.T) {
 		Address:   "cosmos1...",
 	}
 	assert.Equal(t, expectedLog, logs[0])
+
+	// a second validator on the same chain
+	err = sqlitedb.AddValidator("chain1", "cosmos2...")
+	assert.NoError(t, err)
+
+	val, err = sqlitedb.GetChainValidators("chain1")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cosmos1...", "cosmos2..."}, val)
+
+	validator, err := sqlitedb.GetValidator("cosmos2...")
+	assert.NoError(t, err)
+	assert.Equal(t, Validator{ChainName: "chain1", Address: "cosmos2..."}, validator)
+
 	sqlitedb.RemoveValidator("cosmos1...")
+	sqlitedb.RemoveValidator("cosmos2...")
 	logs, err = sqlitedb.GetValidators()
 	assert.NoError(t, err)
 
@@ -61,13 +91,56 @@ func TestValidators(t *testing.T) {
 	assert.Equal(t, expectedLen, len(logs))
 }
 
+func TestMigrateValidators(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	// tables as created before multiple validators per chain were supported
+	_, err = db.Exec("CREATE TABLE validators (chainName VARCHAR PRIMARY KEY, address VARCHAR )")
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+	_, err = db.Exec("CREATE TABLE logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR, proposalTitle VARCHAR)")
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+	_, err = db.Exec("INSERT INTO validators(chainName, address) values('chain1', 'cosmos1...')")
+	if err != nil {
+		t.Fatalf("Failed to insert validator: %v", err)
+	}
+	_, err = db.Exec("INSERT INTO logs(date, chainName, proposalId, voteOption, proposalTitle) values(1, 'chain1', 'proposal1', 'yes', 'title')")
+	if err != nil {
+		t.Fatalf("Failed to insert log: %v", err)
+	}
+
+	sqlitedb := &Sqlitedb{db: db}
+	assert.NoError(t, sqlitedb.InitializeTables())
+
+	// running it again must not fail on the migrated tables
+	assert.NoError(t, sqlitedb.InitializeTables())
+
+	assert.NoError(t, sqlitedb.AddValidator("chain1", "cosmos2..."))
+	val, err := sqlitedb.GetChainValidators("chain1")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cosmos1...", "cosmos2..."}, val)
+
+	logs, err := sqlitedb.GetVoteLogs("chain1", "1970-01-01", "")
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "cosmos1...", logs[0].ValidatorAddress)
+	assert.False(t, logs[0].Confirmed)
+}
+
 func TestVoteLogs(t *testing.T) {
 	db, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
 	defer db.Close()
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR)")
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR, validatorAddress VARCHAR DEFAULT '', txHash VARCHAR DEFAULT '', confirmed BOOLEAN DEFAULT 0)")
 	if err != nil {
 		fmt.Println(err)
 		t.Fatalf("Failed to create test table: %v", err)
@@ -78,7 +151,7 @@ func TestVoteLogs(t *testing.T) {
 	}
 	sqlitedb := &Sqlitedb{db: db}
 
-	err = sqlitedb.AddLog("chain1", "proposaltitle", "proposal1", "yes")
+	err = sqlitedb.AddLog("chain1", "val1", "proposaltitle", "proposal1", "yes")
 	assert.NoError(t, err)
 
 	logs, err := sqlitedb.GetVoteLogs("chain1", "2007-04-01", "")
@@ -86,42 +159,110 @@ func TestVoteLogs(t *testing.T) {
 	assert.Len(t, logs, 1)
 
 	expectedLog := voteLogs{
-		Date:          time.Now().UTC().Unix(),
-		ChainName:     "chain1",
-		ProposalTitle: "proposaltitle",
-		ProposalID:    "proposal1",
-		VoteOption:    "yes",
+		Date:             time.Now().UTC().Unix(),
+		ChainName:        "chain1",
+		ValidatorAddress: "val1",
+		ProposalTitle:    "proposaltitle",
+		ProposalID:       "proposal1",
+		VoteOption:       "yes",
 	}
 	assert.Equal(t, expectedLog, logs[0])
 
-	err = sqlitedb.AddLog("chain1", "proposaltitle2", "proposal2", "")
+	err = sqlitedb.AddLog("chain1", "val1", "proposaltitle2", "proposal2", "")
 	assert.NoError(t, err)
 
-	err = sqlitedb.UpdateVoteLog("chain1", "proposal2", "no")
+	err = sqlitedb.UpdateVoteLog("chain1", "val1", "proposal2", "no")
 	assert.NoError(t, err)
 
 	// vote on non-existing proposal, this will not be stored in logs
-	err = sqlitedb.UpdateVoteLog("chain1", "proposal3", "yes")
+	err = sqlitedb.UpdateVoteLog("chain1", "val1", "proposal3", "yes")
+	assert.NoError(t, err)
+
+	// the second validator of the chain has its own log of the proposal
+	err = sqlitedb.AddLog("chain1", "val2", "proposaltitle2", "proposal2", "")
+	assert.NoError(t, err)
+
+	err = sqlitedb.UpdateVoteLog("chain1", "val2", "proposal2", "yes")
 	assert.NoError(t, err)
 
 	logs, err = sqlitedb.GetVoteLogs("chain1", "2007-04-01", "")
 	assert.NoError(t, err)
+	assert.Len(t, logs, 3)
+
+	expectedLogs := []voteLogs{
+		{
+			Date:             time.Now().UTC().Unix(),
+			ChainName:        "chain1",
+			ValidatorAddress: "val1",
+			ProposalTitle:    "proposaltitle",
+			ProposalID:       "proposal1",
+			VoteOption:       "yes",
+		},
+		{
+			Date:             time.Now().UTC().Unix(),
+			ChainName:        "chain1",
+			ValidatorAddress: "val1",
+			ProposalTitle:    "proposaltitle2",
+			ProposalID:       "proposal2",
+			VoteOption:       "no",
+		},
+		{
+			Date:             time.Now().UTC().Unix(),
+			ChainName:        "chain1",
+			ValidatorAddress: "val2",
+			ProposalTitle:    "proposaltitle2",
+			ProposalID:       "proposal2",
+			VoteOption:       "yes",
+		},
+	}
+	assert.Equal(t, expectedLogs, logs)
+}
+
+func TestConfirmVoteLog(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	// logs table as created before votes were confirmed on chain
+	_, err = db.Exec("CREATE TABLE logs (date INTEGER, chainName VARCHAR, proposalId VARCHAR, voteOption VARCHAR, proposalTitle VARCHAR, validatorAddress VARCHAR DEFAULT '')")
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+
+	sqlitedb := &Sqlitedb{db: db}
+	assert.NoError(t, sqlitedb.InitializeTables())
+
+	err = sqlitedb.AddLog("chain1", "val1", "proposaltitle", "proposal1", "")
+	assert.NoError(t, err)
+	err = sqlitedb.AddLog("chain1", "val2", "proposaltitle", "proposal1", "")
+	assert.NoError(t, err)
+
+	err = sqlitedb.ConfirmVoteLog("chain1", "val1", "proposal1", "yes", "ABCDEF")
+	assert.NoError(t, err)
+
+	logs, err := sqlitedb.GetVoteLogs("chain1", "2007-04-01", "")
+	assert.NoError(t, err)
 	assert.Len(t, logs, 2)
 
 	expectedLogs := []voteLogs{
 		{
-			Date:          time.Now().UTC().Unix(),
-			ChainName:     "chain1",
-			ProposalTitle: "proposaltitle",
-			ProposalID:    "proposal1",
-			VoteOption:    "yes",
+			Date:             time.Now().UTC().Unix(),
+			ChainName:        "chain1",
+			ValidatorAddress: "val1",
+			ProposalTitle:    "proposaltitle",
+			ProposalID:       "proposal1",
+			VoteOption:       "yes",
+			TxHash:           "ABCDEF",
+			Confirmed:        true,
 		},
 		{
-			Date:          time.Now().UTC().Unix(),
-			ChainName:     "chain1",
-			ProposalTitle: "proposaltitle2",
-			ProposalID:    "proposal2",
-			VoteOption:    "no",
+			Date:             time.Now().UTC().Unix(),
+			ChainName:        "chain1",
+			ValidatorAddress: "val2",
+			ProposalTitle:    "proposaltitle",
+			ProposalID:       "proposal1",
 		},
 	}
 	assert.Equal(t, expectedLogs, logs)
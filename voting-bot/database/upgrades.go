@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	// Statuses of a software upgrade
+	UpgradeUpcoming  = "upcoming"
+	UpgradeDone      = "done"
+	UpgradeCancelled = "cancelled"
+)
+
+// Upgrade is a software upgrade planned on a chain
+type Upgrade struct {
+	ChainName  string `json:"chainName"`
+	Name       string `json:"name"`
+	Height     int64  `json:"height"`
+	Info       string `json:"info"`
+	ProposalID string `json:"proposalID"`
+	// EstimatedTime is the unix time the upgrade height is expected to be
+	// reached at, based on recent block times
+	EstimatedTime int64  `json:"estimatedTime"`
+	Status        string `json:"status"`
+	UpdatedAt     int64  `json:"updatedAt"`
+}
+
+// Gets the upgrade of the chain by its plan name, found is false if it is
+// not stored
+func (a *Sqlitedb) GetUpgrade(chainName, name string) (Upgrade, bool, error) {
+	rows, err := a.db.Query("SELECT chainName, name, height, info, proposalId, estimatedTime, status, updatedAt FROM upgrades WHERE chainName = ? AND name = ?",
+		chainName, name)
+	if err != nil {
+		return Upgrade{}, false, err
+	}
+	defer rows.Close()
+
+	upgrades, err := scanUpgrades(rows)
+	if err != nil || len(upgrades) == 0 {
+		return Upgrade{}, false, err
+	}
+
+	return upgrades[0], true, nil
+}
+
+// Stores the upgrade, replacing the stored upgrade of the same name
+func (a *Sqlitedb) SetUpgrade(upgrade Upgrade) error {
+	stmt, err := a.db.Prepare("INSERT OR REPLACE INTO upgrades(chainName, name, height, info, proposalId, estimatedTime, status, updatedAt) values(?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(upgrade.ChainName, upgrade.Name, upgrade.Height, upgrade.Info, upgrade.ProposalID,
+		upgrade.EstimatedTime, upgrade.Status, time.Now().UTC().Unix())
+	return err
+}
+
+// Gets the upgrades with the status, of the chain or of all chains if
+// chainName is empty, the earliest first
+func (a *Sqlitedb) GetUpgrades(chainName, status string) ([]Upgrade, error) {
+	query := "SELECT chainName, name, height, info, proposalId, estimatedTime, status, updatedAt FROM upgrades WHERE status = ?"
+	args := []interface{}{status}
+	if chainName != "" {
+		query += " AND chainName = ?"
+		args = append(args, chainName)
+	}
+	query += " ORDER BY estimatedTime, chainName"
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanUpgrades(rows)
+}
+
+// Checks if the countdown reminder of the threshold was already sent for the
+// upgrade
+func (a *Sqlitedb) HasUpgradeReminder(chainName, name string, threshold time.Duration) (bool, error) {
+	var exists bool
+	err := a.db.QueryRow("SELECT EXISTS(SELECT 1 FROM upgrade_reminders WHERE chainName = ? AND name = ? AND threshold = ?)",
+		chainName, name, int64(threshold.Seconds())).Scan(&exists)
+	return exists, err
+}
+
+// Marks the countdown reminder of the threshold as sent for the upgrade
+func (a *Sqlitedb) AddUpgradeReminder(chainName, name string, threshold time.Duration) error {
+	stmt, err := a.db.Prepare("INSERT OR IGNORE INTO upgrade_reminders(chainName, name, threshold, date) values(?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(chainName, name, int64(threshold.Seconds()), time.Now().UTC().Unix())
+	return err
+}
+
+func scanUpgrades(rows *sql.Rows) ([]Upgrade, error) {
+	var upgrades []Upgrade
+	for rows.Next() {
+		var data Upgrade
+		if err := rows.Scan(&data.ChainName, &data.Name, &data.Height, &data.Info, &data.ProposalID,
+			&data.EstimatedTime, &data.Status, &data.UpdatedAt); err != nil {
+			return upgrades, err
+		}
+		upgrades = append(upgrades, data)
+	}
+
+	return upgrades, rows.Err()
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgrades(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	_, found, err := sqlitedb.GetUpgrade("chain1", "v2")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	upgrade := Upgrade{ChainName: "chain1", Name: "v2", Height: 1000, ProposalID: "5", EstimatedTime: 200, Status: UpgradeUpcoming}
+	assert.NoError(t, sqlitedb.SetUpgrade(upgrade))
+	assert.NoError(t, sqlitedb.SetUpgrade(Upgrade{ChainName: "chain2", Name: "v9", Height: 50, EstimatedTime: 100, Status: UpgradeUpcoming}))
+	assert.NoError(t, sqlitedb.SetUpgrade(Upgrade{ChainName: "chain2", Name: "v8", Height: 10, EstimatedTime: 10, Status: UpgradeDone}))
+
+	// the estimate is updated in place
+	upgrade.EstimatedTime = 300
+	assert.NoError(t, sqlitedb.SetUpgrade(upgrade))
+
+	stored, found, err := sqlitedb.GetUpgrade("chain1", "v2")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(300), stored.EstimatedTime)
+	assert.Equal(t, "5", stored.ProposalID)
+
+	upcoming, err := sqlitedb.GetUpgrades("", UpgradeUpcoming)
+	assert.NoError(t, err)
+	assert.Len(t, upcoming, 2)
+	assert.Equal(t, "v9", upcoming[0].Name)
+	assert.Equal(t, "v2", upcoming[1].Name)
+
+	upcoming, err = sqlitedb.GetUpgrades("chain1", UpgradeUpcoming)
+	assert.NoError(t, err)
+	assert.Len(t, upcoming, 1)
+
+	sent, err := sqlitedb.HasUpgradeReminder("chain1", "v2", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	assert.NoError(t, sqlitedb.AddUpgradeReminder("chain1", "v2", time.Hour))
+	assert.NoError(t, sqlitedb.AddUpgradeReminder("chain1", "v2", time.Hour))
+
+	sent, err = sqlitedb.HasUpgradeReminder("chain1", "v2", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	sent, err = sqlitedb.HasUpgradeReminder("chain1", "v2", 15*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+}
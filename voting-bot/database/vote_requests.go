@@ -0,0 +1,228 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// Vote request types
+	VoteTypeSingle   = "vote"
+	VoteTypeWeighted = "weighted vote"
+	// VoteTypeBatch votes on several proposals, its vote option holds the
+	// proposalId=option pairs
+	VoteTypeBatch = "batch vote"
+
+	// Vote request statuses
+	VoteRequestPending  = "pending"
+	VoteRequestApproved = "approved"
+	VoteRequestExecuted = "executed"
+	VoteRequestFailed   = "failed"
+	VoteRequestRejected = "rejected"
+	VoteRequestExpired  = "expired"
+	// A simulated vote waits for its requester to confirm or cancel it
+	VoteRequestUnconfirmed = "unconfirmed"
+	VoteRequestCancelled   = "cancelled"
+)
+
+type (
+	// VoteRequest is a vote waiting for the approval of other users before
+	// it is broadcasted
+	VoteRequest struct {
+		ID int64
+		// Target is the chain name or the validator address to vote for
+		Target      string
+		ProposalID  string
+		VoteType    string
+		VoteOption  string
+		GasPrices   string
+		Memo        string
+		Metadata    string
+		RequestedBy string
+		Status      string
+		Approvals   int
+		CreatedAt   int64
+		ExpiresAt   int64
+	}
+
+	// VoteAudit is an entry in the audit trail of vote requests
+	VoteAudit struct {
+		Date      int64
+		RequestID int64
+		UserID    string
+		Action    string
+		Details   string
+	}
+)
+
+const voteRequestColumns = "id, target, proposalId, voteType, voteOption, gasPrices, memo, metadata, requestedBy, status, createdAt, expiresAt, (SELECT COUNT(*) FROM vote_approvals WHERE requestId = vote_requests.id)"
+
+// Stores a vote request, which expires after the timeout. The request is
+// pending unless it is given another status.
+func (a *Sqlitedb) AddVoteRequest(req VoteRequest, timeout time.Duration) (int64, error) {
+	stmt, err := a.db.Prepare("INSERT INTO vote_requests(target, proposalId, voteType, voteOption, gasPrices, memo, metadata, requestedBy, status, createdAt, expiresAt) values(?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return 0, err
+	}
+
+	defer stmt.Close()
+
+	status := req.Status
+	if status == "" {
+		status = VoteRequestPending
+	}
+
+	now := time.Now().UTC()
+	res, err := stmt.Exec(req.Target, req.ProposalID, req.VoteType, req.VoteOption, req.GasPrices, req.Memo, req.Metadata,
+		req.RequestedBy, status, now.Unix(), now.Add(timeout).Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// Gets a vote request along with the number of approvals it has
+func (a *Sqlitedb) GetVoteRequest(id int64) (VoteRequest, error) {
+	row := a.db.QueryRow("SELECT "+voteRequestColumns+" FROM vote_requests WHERE id = ?", id)
+
+	req, err := scanVoteRequest(row)
+	if err == sql.ErrNoRows {
+		return VoteRequest{}, fmt.Errorf("vote request %d does not exist", id)
+	}
+
+	return req, err
+}
+
+// Gets all vote requests waiting for approvals
+func (a *Sqlitedb) GetPendingVoteRequests() ([]VoteRequest, error) {
+	rows, err := a.db.Query("SELECT "+voteRequestColumns+" FROM vote_requests WHERE status = ? ORDER BY id", VoteRequestPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []VoteRequest
+	for rows.Next() {
+		req, err := scanVoteRequest(rows)
+		if err != nil {
+			return requests, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// Stores the approval of the user and returns the number of approvals of the
+// request. A user can approve a request only once.
+func (a *Sqlitedb) AddVoteApproval(id int64, userID string) (int, error) {
+	var exists bool
+	err := a.db.QueryRow("SELECT EXISTS(SELECT 1 FROM vote_approvals WHERE requestId = ? AND userId = ?)", id, userID).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+
+	if exists {
+		return 0, fmt.Errorf("vote request %d is already approved by the user", id)
+	}
+
+	_, err = a.db.Exec("INSERT INTO vote_approvals(requestId, userId, date) values(?,?,?)", id, userID, time.Now().UTC().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	var approvals int
+	err = a.db.QueryRow("SELECT COUNT(*) FROM vote_approvals WHERE requestId = ?", id).Scan(&approvals)
+	return approvals, err
+}
+
+// Moves the vote request from one status to another. It returns false if the
+// request is no longer in the from status, so that a request is only acted
+// on once.
+func (a *Sqlitedb) UpdateVoteRequestStatus(id int64, from, to string) (bool, error) {
+	res, err := a.db.Exec("UPDATE vote_requests SET status = ? WHERE id = ? AND status = ?", to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated == 1, nil
+}
+
+// Expires the pending vote requests whose timeout has passed and returns them
+func (a *Sqlitedb) ExpireVoteRequests() ([]VoteRequest, error) {
+	pending, err := a.GetPendingVoteRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Unix()
+	var expired []VoteRequest
+	for _, req := range pending {
+		if req.ExpiresAt > now {
+			continue
+		}
+
+		updated, err := a.UpdateVoteRequestStatus(req.ID, VoteRequestPending, VoteRequestExpired)
+		if err != nil {
+			return expired, err
+		}
+
+		if updated {
+			req.Status = VoteRequestExpired
+			expired = append(expired, req)
+		}
+	}
+
+	return expired, nil
+}
+
+// Adds an entry to the audit trail of a vote request
+func (a *Sqlitedb) AddVoteAudit(requestID int64, userID, action, details string) error {
+	stmt, err := a.db.Prepare("INSERT INTO vote_audit(date, requestId, userId, action, details) values(?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.Exec(time.Now().UTC().Unix(), requestID, userID, action, details)
+	return err
+}
+
+// Gets the audit trail of a vote request
+func (a *Sqlitedb) GetVoteAudit(requestID int64) ([]VoteAudit, error) {
+	rows, err := a.db.Query("SELECT date, requestId, userId, action, details FROM vote_audit WHERE requestId = ? ORDER BY rowid", requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []VoteAudit
+	for rows.Next() {
+		var data VoteAudit
+		if err := rows.Scan(&data.Date, &data.RequestID, &data.UserID, &data.Action, &data.Details); err != nil {
+			return entries, err
+		}
+		entries = append(entries, data)
+	}
+
+	return entries, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVoteRequest(row rowScanner) (VoteRequest, error) {
+	var req VoteRequest
+	err := row.Scan(&req.ID, &req.Target, &req.ProposalID, &req.VoteType, &req.VoteOption, &req.GasPrices, &req.Memo,
+		&req.Metadata, &req.RequestedBy, &req.Status, &req.CreatedAt, &req.ExpiresAt, &req.Approvals)
+	return req, err
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoteRequests(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	id, err := sqlitedb.AddVoteRequest(VoteRequest{
+		Target:      "cosmoshub",
+		ProposalID:  "12",
+		VoteType:    VoteTypeSingle,
+		VoteOption:  "yes",
+		GasPrices:   "0.25uatom",
+		RequestedBy: "U1",
+	}, time.Hour)
+	assert.NoError(t, err)
+
+	req, err := sqlitedb.GetVoteRequest(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "cosmoshub", req.Target)
+	assert.Equal(t, VoteRequestPending, req.Status)
+	assert.Equal(t, 0, req.Approvals)
+
+	approvals, err := sqlitedb.AddVoteApproval(id, "U2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, approvals)
+
+	// the same user can not approve twice
+	_, err = sqlitedb.AddVoteApproval(id, "U2")
+	assert.Error(t, err)
+
+	approvals, err = sqlitedb.AddVoteApproval(id, "U3")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, approvals)
+
+	pending, err := sqlitedb.GetPendingVoteRequests()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Approvals)
+
+	updated, err := sqlitedb.UpdateVoteRequestStatus(id, VoteRequestPending, VoteRequestApproved)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	// a request is only moved out of a status once
+	updated, err = sqlitedb.UpdateVoteRequestStatus(id, VoteRequestPending, VoteRequestApproved)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	pending, err = sqlitedb.GetPendingVoteRequests()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	_, err = sqlitedb.GetVoteRequest(id + 1)
+	assert.Error(t, err)
+}
+
+func TestExpireVoteRequests(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	expiredID, err := sqlitedb.AddVoteRequest(VoteRequest{Target: "cosmoshub", ProposalID: "1", RequestedBy: "U1"}, -time.Minute)
+	assert.NoError(t, err)
+	_, err = sqlitedb.AddVoteRequest(VoteRequest{Target: "cosmoshub", ProposalID: "2", RequestedBy: "U1"}, time.Hour)
+	assert.NoError(t, err)
+
+	expired, err := sqlitedb.ExpireVoteRequests()
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, expiredID, expired[0].ID)
+	assert.Equal(t, VoteRequestExpired, expired[0].Status)
+
+	expired, err = sqlitedb.ExpireVoteRequests()
+	assert.NoError(t, err)
+	assert.Len(t, expired, 0)
+
+	pending, err := sqlitedb.GetPendingVoteRequests()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "2", pending[0].ProposalID)
+}
+
+func TestVoteAudit(t *testing.T) {
+	sqlitedb := newTestDB(t)
+
+	assert.NoError(t, sqlitedb.AddVoteAudit(1, "U1", "requested", "yes on cosmoshub proposal 12"))
+	assert.NoError(t, sqlitedb.AddVoteAudit(1, "U2", "approved", "1/1 approvals"))
+	assert.NoError(t, sqlitedb.AddVoteAudit(2, "U1", "requested", "no on osmosis proposal 3"))
+
+	entries, err := sqlitedb.GetVoteAudit(1)
+	assert.NoError(t, err)
+
+	expectedEntries := []VoteAudit{
+		{
+			Date:      time.Now().UTC().Unix(),
+			RequestID: 1,
+			UserID:    "U1",
+			Action:    "requested",
+			Details:   "yes on cosmoshub proposal 12",
+		},
+		{
+			Date:      time.Now().UTC().Unix(),
+			RequestID: 1,
+			UserID:    "U2",
+			Action:    "approved",
+			Details:   "1/1 approvals",
+		},
+	}
+	assert.Equal(t, expectedEntries, entries)
+}
@@ -0,0 +1,159 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// maxInfoLength keeps the upgrade info, which often lists the binaries of
+// the upgrade, short
+const maxInfoLength = 300
+
+func init() {
+	Register("/cosmos.gov.v1beta1.TextProposal", decodeTextProposal)
+	Register("/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal", decodeSoftwareUpgrade)
+	Register("/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade", decodeSoftwareUpgrade)
+	Register("/cosmos.upgrade.v1beta1.CancelSoftwareUpgradeProposal", decodeCancelUpgrade)
+	Register("/cosmos.upgrade.v1beta1.MsgCancelUpgrade", decodeCancelUpgrade)
+	Register("/cosmos.params.v1beta1.ParameterChangeProposal", decodeParameterChange)
+	Register("/cosmos.distribution.v1beta1.CommunityPoolSpendProposal", decodeCommunityPoolSpend)
+	Register("/cosmos.distribution.v1beta1.MsgCommunityPoolSpend", decodeCommunityPoolSpend)
+}
+
+func decodeTextProposal(raw json.RawMessage) (string, error) {
+	return "Text proposal without on-chain changes", nil
+}
+
+func decodeSoftwareUpgrade(raw json.RawMessage) (string, error) {
+	var msg struct {
+		Plan types.Plan `json:"plan"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	return formatPlan("Software upgrade", msg.Plan)
+}
+
+func decodeCancelUpgrade(raw json.RawMessage) (string, error) {
+	return "Cancels the planned software upgrade", nil
+}
+
+func decodeParameterChange(raw json.RawMessage) (string, error) {
+	var msg struct {
+		Changes []struct {
+			Subspace string `json:"subspace"`
+			Key      string `json:"key"`
+			Value    string `json:"value"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	if len(msg.Changes) == 0 {
+		return "", errMissingField
+	}
+
+	lines := []string{"Parameter changes:"}
+	for _, change := range msg.Changes {
+		lines = append(lines, fmt.Sprintf("• %s/%s = %s", change.Subspace, change.Key, change.Value))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// decodeUpdateParams returns the decoder of the MsgUpdateParams of a module,
+// which sets all params of the module at once
+func decodeUpdateParams(typeURL string) Decoder {
+	return func(raw json.RawMessage) (string, error) {
+		var msg struct {
+			Params map[string]json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return "", err
+		}
+
+		if len(msg.Params) == 0 {
+			return "", errMissingField
+		}
+
+		var keys []string
+		for key := range msg.Params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		lines := []string{fmt.Sprintf("Sets the params of the %s module:", moduleName(typeURL))}
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("• %s = %s", key, compactJSON(msg.Params[key])))
+		}
+
+		return strings.Join(lines, "\n"), nil
+	}
+}
+
+func decodeCommunityPoolSpend(raw json.RawMessage) (string, error) {
+	var msg struct {
+		Recipient string       `json:"recipient"`
+		Amount    []types.Coin `json:"amount"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	if msg.Recipient == "" {
+		return "", errMissingField
+	}
+
+	return fmt.Sprintf("Community pool spend of %s to %s", formatCoins(msg.Amount), msg.Recipient), nil
+}
+
+func formatPlan(heading string, p types.Plan) (string, error) {
+	if p.Name == "" {
+		return "", errMissingField
+	}
+
+	summary := fmt.Sprintf("%s *%s* at height %s", heading, p.Name, p.Height)
+	if p.Info != "" {
+		info := []rune(p.Info)
+		if len(info) > maxInfoLength {
+			info = append(info[:maxInfoLength], '…')
+		}
+		summary += "\nInfo: " + string(info)
+	}
+
+	return summary, nil
+}
+
+func formatCoins(coins []types.Coin) string {
+	if len(coins) == 0 {
+		return "nothing"
+	}
+
+	var out []string
+	for _, coin := range coins {
+		out = append(out, coin.Amount+coin.Denom)
+	}
+
+	return strings.Join(out, ", ")
+}
+
+// moduleName returns the package of the message type without its version,
+// e.g. cosmos.staking for /cosmos.staking.v1beta1.MsgUpdateParams
+func moduleName(typeURL string) string {
+	parts := strings.Split(strings.TrimPrefix(typeURL, "/"), ".")
+	if len(parts) > 2 && strings.HasPrefix(parts[len(parts)-2], "v") {
+		return strings.Join(parts[:len(parts)-2], ".")
+	}
+
+	if len(parts) > 1 {
+		return strings.Join(parts[:len(parts)-1], ".")
+	}
+
+	return typeURL
+}
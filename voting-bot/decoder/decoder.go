@@ -0,0 +1,107 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type (
+	// Decoder turns the JSON of a proposal message into a readable summary
+	Decoder func(raw json.RawMessage) (string, error)
+
+	// Message is a proposal message, or the content of a legacy proposal,
+	// along with its summary. Decoded is false if the type has no decoder
+	// and the summary is the raw JSON of the message.
+	Message struct {
+		Type    string
+		Summary string
+		Decoded bool
+	}
+)
+
+var registry = make(map[string]Decoder)
+
+// errMissingField is returned by decoders when the message lacks a field
+// they summarize, so the message falls back to raw JSON
+var errMissingField = errors.New("missing field")
+
+// Register adds the decoder of the message type, replacing an existing one
+func Register(typeURL string, decoder Decoder) {
+	registry[typeURL] = decoder
+}
+
+// Decode summarizes the message with the decoder registered for its type.
+// Messages without a decoder, or which fail to decode, are summarized as
+// indented JSON.
+func Decode(raw json.RawMessage) Message {
+	var header struct {
+		Type    string          `json:"@type"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return Message{Summary: string(raw)}
+	}
+
+	// the legacy content of a gov v1 proposal is decoded on its own
+	if header.Type == "/cosmos.gov.v1.MsgExecLegacyContent" && len(header.Content) > 0 {
+		return Decode(header.Content)
+	}
+
+	message := Message{Type: header.Type}
+	if decoder := lookup(header.Type); decoder != nil {
+		summary, err := decoder(raw)
+		if err == nil {
+			message.Summary, message.Decoded = summary, true
+			return message
+		}
+	}
+
+	message.Summary = rawJSON(raw)
+	return message
+}
+
+// lookup returns the decoder of the type. The MsgUpdateParams of all modules
+// share a decoder unless one is registered for the type.
+func lookup(typeURL string) Decoder {
+	if decoder, ok := registry[typeURL]; ok {
+		return decoder
+	}
+
+	if strings.HasSuffix(typeURL, ".MsgUpdateParams") {
+		return decodeUpdateParams(typeURL)
+	}
+
+	return nil
+}
+
+// rawJSON formats the fields of a message, other than its type, as indented
+// JSON
+func rawJSON(raw json.RawMessage) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return string(raw)
+	}
+	delete(fields, "@type")
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fields); err != nil {
+		return string(raw)
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// compactJSON formats a value of a message on a single line
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+
+	return buf.String()
+}
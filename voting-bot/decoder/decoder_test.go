@@ -0,0 +1,292 @@
+package decoder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// wasm is the smallest valid wasm module, its magic number and version
+var wasm = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+func wasmSum() string {
+	sum := sha256.Sum256(wasm)
+	return hex.EncodeToString(sum[:])
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeRegisteredTypes(t *testing.T) {
+	code := base64.StdEncoding.EncodeToString(wasm)
+	storeSummary := fmt.Sprintf("Stores wasm code with checksum `%s` (8 bytes)", wasmSum())
+
+	tests := []struct {
+		typeURL string
+		fields  string
+		want    string
+	}{
+		{
+			typeURL: "/cosmos.gov.v1beta1.TextProposal",
+			fields:  `"title":"Signal","description":"..."`,
+			want:    "Text proposal without on-chain changes",
+		},
+		{
+			typeURL: "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal",
+			fields:  `"plan":{"name":"v15","height":"1000","info":""}`,
+			want:    "Software upgrade *v15* at height 1000",
+		},
+		{
+			typeURL: "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade",
+			fields:  `"authority":"cosmos10d07y","plan":{"name":"v16","height":"2000","info":"{\"binaries\":{}}"}`,
+			want:    "Software upgrade *v16* at height 2000\nInfo: {\"binaries\":{}}",
+		},
+		{
+			typeURL: "/cosmos.upgrade.v1beta1.CancelSoftwareUpgradeProposal",
+			want:    "Cancels the planned software upgrade",
+		},
+		{
+			typeURL: "/cosmos.upgrade.v1beta1.MsgCancelUpgrade",
+			fields:  `"authority":"cosmos10d07y"`,
+			want:    "Cancels the planned software upgrade",
+		},
+		{
+			typeURL: "/cosmos.params.v1beta1.ParameterChangeProposal",
+			fields:  `"changes":[{"subspace":"staking","key":"MaxValidators","value":"180"},{"subspace":"gov","key":"votingparams","value":"{}"}]`,
+			want:    "Parameter changes:\n• staking/MaxValidators = 180\n• gov/votingparams = {}",
+		},
+		{
+			typeURL: "/cosmos.distribution.v1beta1.CommunityPoolSpendProposal",
+			fields:  `"recipient":"cosmos1abc","amount":[{"denom":"uatom","amount":"100"},{"denom":"uosmo","amount":"5"}]`,
+			want:    "Community pool spend of 100uatom, 5uosmo to cosmos1abc",
+		},
+		{
+			typeURL: "/cosmos.distribution.v1beta1.MsgCommunityPoolSpend",
+			fields:  `"authority":"cosmos10d07y","recipient":"cosmos1abc","amount":[]`,
+			want:    "Community pool spend of nothing to cosmos1abc",
+		},
+		{
+			typeURL: "/ibc.core.client.v1.ClientUpdateProposal",
+			fields:  `"subject_client_id":"07-tendermint-1","substitute_client_id":"07-tendermint-2"`,
+			want:    "IBC client update: replaces client *07-tendermint-1* with substitute client *07-tendermint-2*",
+		},
+		{
+			typeURL: "/ibc.core.client.v1.MsgRecoverClient",
+			fields:  `"subject_client_id":"07-tendermint-3","substitute_client_id":"07-tendermint-4","signer":"cosmos10d07y"`,
+			want:    "IBC client update: replaces client *07-tendermint-3* with substitute client *07-tendermint-4*",
+		},
+		{
+			typeURL: "/ibc.core.client.v1.UpgradeProposal",
+			fields:  `"plan":{"name":"ibc","height":"500"},"upgraded_client_state":{"@type":"/ibc.lightclients.tendermint.v1.ClientState","chain_id":"cosmoshub-5"}`,
+			want:    "IBC software upgrade *ibc* at height 500\nUpgraded client chain id: cosmoshub-5",
+		},
+		{
+			typeURL: "/ibc.core.client.v1.MsgIBCSoftwareUpgrade",
+			fields:  `"plan":{"name":"ibc","height":"600"}`,
+			want:    "IBC software upgrade *ibc* at height 600",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.StoreCodeProposal",
+			fields:  fmt.Sprintf(`"run_as":"juno1runas","wasm_byte_code":%q`, code),
+			want:    storeSummary + " as juno1runas",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.MsgStoreCode",
+			fields:  fmt.Sprintf(`"sender":"juno1sender","wasm_byte_code":%q`, code),
+			want:    storeSummary + " as juno1sender",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.StoreAndInstantiateContractProposal",
+			fields:  fmt.Sprintf(`"authority":"juno1gov","wasm_byte_code":%q`, code),
+			want:    storeSummary + " as juno1gov",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.MigrateContractProposal",
+			fields:  `"contract":"juno1contract","code_id":"42","msg":{ "migrate": {} }`,
+			want:    "Migrates contract juno1contract to code 42\nMigrate msg: {\"migrate\":{}}",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.MsgMigrateContract",
+			fields:  `"contract":"juno1contract","code_id":"43"`,
+			want:    "Migrates contract juno1contract to code 43",
+		},
+		{
+			typeURL: "/cosmwasm.wasm.v1.MsgStoreAndMigrateContract",
+			fields:  fmt.Sprintf(`"authority":"juno1gov","wasm_byte_code":%q,"contract":"juno1contract","msg":{}`, code),
+			want:    storeSummary + " as juno1gov\nMigrates contract juno1contract to the stored code\nMigrate msg: {}",
+		},
+	}
+
+	tested := make(map[string]bool)
+	for _, tt := range tests {
+		tested[tt.typeURL] = true
+		t.Run(tt.typeURL, func(t *testing.T) {
+			raw := fmt.Sprintf(`{"@type":%q}`, tt.typeURL)
+			if tt.fields != "" {
+				raw = fmt.Sprintf(`{"@type":%q,%s}`, tt.typeURL, tt.fields)
+			}
+
+			message := Decode(json.RawMessage(raw))
+			assert.Equal(t, Message{Type: tt.typeURL, Summary: tt.want, Decoded: true}, message)
+		})
+	}
+
+	for typeURL := range registry {
+		assert.True(t, tested[typeURL], "no test for %s", typeURL)
+	}
+}
+
+func TestDecodeUpdateParams(t *testing.T) {
+	message := Decode(json.RawMessage(`{"@type":"/cosmos.staking.v1beta1.MsgUpdateParams","authority":"cosmos10d07y","params":{"unbonding_time":"1814400s","max_validators":180}}`))
+	assert.Equal(t, Message{
+		Type:    "/cosmos.staking.v1beta1.MsgUpdateParams",
+		Summary: "Sets the params of the cosmos.staking module:\n• max_validators = 180\n• unbonding_time = \"1814400s\"",
+		Decoded: true,
+	}, message)
+
+	// a module without a version in its package
+	message = Decode(json.RawMessage(`{"@type":"/osmosis.MsgUpdateParams","params":{"a":1}}`))
+	assert.Equal(t, "Sets the params of the osmosis module:\n• a = 1", message.Summary)
+}
+
+func TestWasmChecksum(t *testing.T) {
+	checksum, err := wasmChecksum(wasm)
+	assert.NoError(t, err)
+	assert.Equal(t, wasmSum(), checksum)
+
+	// the checksum of gzipped code is the one of the uncompressed code
+	checksum, err = wasmChecksum(gzipped(t, wasm))
+	assert.NoError(t, err)
+	assert.Equal(t, wasmSum(), checksum)
+
+	_, err = wasmChecksum([]byte{0x1f, 0x8b, 0x00})
+	assert.Error(t, err)
+}
+
+func TestDecodeStoreCodeChecksum(t *testing.T) {
+	compressed := gzipped(t, wasm)
+	sum := sha256.Sum256(wasm)
+
+	tests := []struct {
+		name     string
+		code     []byte
+		codeHash string
+		want     string
+	}{
+		{
+			name:     "gzipped code",
+			code:     compressed,
+			codeHash: base64.StdEncoding.EncodeToString(sum[:]),
+			want:     fmt.Sprintf("Stores wasm code with checksum `%s` (%d bytes) as juno1gov", wasmSum(), len(compressed)),
+		},
+		{
+			name:     "checksum mismatch",
+			code:     wasm,
+			codeHash: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+			want: fmt.Sprintf("Stores wasm code with checksum `%s` (8 bytes) as juno1gov\n:warning: the expected checksum of the proposal is `%s`",
+				wasmSum(), strings.Repeat("0", 64)),
+		},
+		{
+			name: "without expected checksum",
+			code: wasm,
+			want: fmt.Sprintf("Stores wasm code with checksum `%s` (8 bytes) as juno1gov", wasmSum()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := fmt.Sprintf(`{"@type":"/cosmwasm.wasm.v1.StoreCodeProposal","authority":"juno1gov","wasm_byte_code":%q,"code_hash":%q}`,
+				base64.StdEncoding.EncodeToString(tt.code), tt.codeHash)
+			message := Decode(json.RawMessage(raw))
+			assert.True(t, message.Decoded)
+			assert.Equal(t, tt.want, message.Summary)
+		})
+	}
+}
+
+func TestDecodeLegacyContent(t *testing.T) {
+	message := Decode(json.RawMessage(`{"@type":"/cosmos.gov.v1.MsgExecLegacyContent","authority":"cosmos10d07y","content":{"@type":"/cosmos.params.v1beta1.ParameterChangeProposal","changes":[{"subspace":"staking","key":"MaxValidators","value":"180"}]}}`))
+	assert.Equal(t, Message{
+		Type:    "/cosmos.params.v1beta1.ParameterChangeProposal",
+		Summary: "Parameter changes:\n• staking/MaxValidators = 180",
+		Decoded: true,
+	}, message)
+
+	// the content of an unknown type falls back to its JSON
+	message = Decode(json.RawMessage(`{"@type":"/cosmos.gov.v1.MsgExecLegacyContent","content":{"@type":"/custom.v1.Proposal","x":1}}`))
+	assert.Equal(t, Message{Type: "/custom.v1.Proposal", Summary: "{\n  \"x\": 1\n}"}, message)
+
+	// without content the message itself is shown
+	message = Decode(json.RawMessage(`{"@type":"/cosmos.gov.v1.MsgExecLegacyContent","authority":"cosmos10d07y"}`))
+	assert.Equal(t, Message{Type: "/cosmos.gov.v1.MsgExecLegacyContent", Summary: "{\n  \"authority\": \"cosmos10d07y\"\n}"}, message)
+}
+
+func TestDecodeRawJSONFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Message
+	}{
+		{
+			name: "unknown type",
+			raw:  `{"@type":"/custom.v1.MsgDoSomething","url":"https://example.com/?a=1&b=<2>","amount":[{"denom":"uatom","amount":"1"}]}`,
+			want: Message{
+				Type:    "/custom.v1.MsgDoSomething",
+				Summary: "{\n  \"amount\": [\n    {\n      \"amount\": \"1\",\n      \"denom\": \"uatom\"\n    }\n  ],\n  \"url\": \"https://example.com/?a=1&b=<2>\"\n}",
+			},
+		},
+		{
+			name: "missing field",
+			raw:  `{"@type":"/cosmos.distribution.v1beta1.MsgCommunityPoolSpend","authority":"cosmos10d07y"}`,
+			want: Message{Type: "/cosmos.distribution.v1beta1.MsgCommunityPoolSpend", Summary: "{\n  \"authority\": \"cosmos10d07y\"\n}"},
+		},
+		{
+			name: "invalid field",
+			raw:  `{"@type":"/cosmwasm.wasm.v1.MsgStoreCode","wasm_byte_code":"not base64"}`,
+			want: Message{Type: "/cosmwasm.wasm.v1.MsgStoreCode", Summary: "{\n  \"wasm_byte_code\": \"not base64\"\n}"},
+		},
+		{
+			name: "empty params",
+			raw:  `{"@type":"/cosmos.gov.v1.MsgUpdateParams","params":{}}`,
+			want: Message{Type: "/cosmos.gov.v1.MsgUpdateParams", Summary: "{\n  \"params\": {}\n}"},
+		},
+		{
+			name: "invalid JSON",
+			raw:  `{"@type":`,
+			want: Message{Summary: `{"@type":`},
+		},
+		{
+			name: "not an object",
+			raw:  `["a"]`,
+			want: Message{Summary: `["a"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Decode(json.RawMessage(tt.raw)))
+		})
+	}
+}
+
+func TestFormatPlanTruncatesInfo(t *testing.T) {
+	summary, err := formatPlan("Software upgrade", types.Plan{Name: "v1", Height: "10", Info: strings.Repeat("é", maxInfoLength+5)})
+	assert.NoError(t, err)
+	assert.Equal(t, "Software upgrade *v1* at height 10\nInfo: "+strings.Repeat("é", maxInfoLength)+"…", summary)
+}
@@ -0,0 +1,58 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+func init() {
+	Register("/ibc.core.client.v1.ClientUpdateProposal", decodeClientRecovery)
+	Register("/ibc.core.client.v1.MsgRecoverClient", decodeClientRecovery)
+	Register("/ibc.core.client.v1.UpgradeProposal", decodeIBCUpgrade)
+	Register("/ibc.core.client.v1.MsgIBCSoftwareUpgrade", decodeIBCUpgrade)
+}
+
+// decodeClientRecovery summarizes the replacement of an expired or frozen
+// IBC client by an active substitute client
+func decodeClientRecovery(raw json.RawMessage) (string, error) {
+	var msg struct {
+		SubjectClientID    string `json:"subject_client_id"`
+		SubstituteClientID string `json:"substitute_client_id"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	if msg.SubjectClientID == "" {
+		return "", errMissingField
+	}
+
+	return fmt.Sprintf("IBC client update: replaces client *%s* with substitute client *%s*",
+		msg.SubjectClientID, msg.SubstituteClientID), nil
+}
+
+func decodeIBCUpgrade(raw json.RawMessage) (string, error) {
+	var msg struct {
+		Plan                types.Plan `json:"plan"`
+		UpgradedClientState struct {
+			Type    string `json:"@type"`
+			ChainID string `json:"chain_id"`
+		} `json:"upgraded_client_state"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	summary, err := formatPlan("IBC software upgrade", msg.Plan)
+	if err != nil {
+		return "", err
+	}
+
+	if msg.UpgradedClientState.ChainID != "" {
+		summary += fmt.Sprintf("\nUpgraded client chain id: %s", msg.UpgradedClientState.ChainID)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,162 @@
+package decoder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type storeCode struct {
+	// the signer is run_as in proposals, sender or authority in messages
+	RunAs        string `json:"run_as"`
+	Sender       string `json:"sender"`
+	Authority    string `json:"authority"`
+	WasmByteCode string `json:"wasm_byte_code"`
+	// CodeHash is the expected checksum in base64, set by proposals of
+	// wasmd v0.40+
+	CodeHash string `json:"code_hash"`
+}
+
+type migrateContract struct {
+	Contract string          `json:"contract"`
+	CodeID   string          `json:"code_id"`
+	Msg      json.RawMessage `json:"msg"`
+}
+
+func init() {
+	Register("/cosmwasm.wasm.v1.StoreCodeProposal", decodeStoreCode)
+	Register("/cosmwasm.wasm.v1.MsgStoreCode", decodeStoreCode)
+	Register("/cosmwasm.wasm.v1.StoreAndInstantiateContractProposal", decodeStoreCode)
+	Register("/cosmwasm.wasm.v1.MigrateContractProposal", decodeMigrateContract)
+	Register("/cosmwasm.wasm.v1.MsgMigrateContract", decodeMigrateContract)
+	Register("/cosmwasm.wasm.v1.MsgStoreAndMigrateContract", decodeStoreAndMigrate)
+}
+
+func decodeStoreCode(raw json.RawMessage) (string, error) {
+	var msg storeCode
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	return formatStoreCode(msg)
+}
+
+func decodeMigrateContract(raw json.RawMessage) (string, error) {
+	var msg migrateContract
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	return formatMigrateContract(msg)
+}
+
+func decodeStoreAndMigrate(raw json.RawMessage) (string, error) {
+	var msg struct {
+		storeCode
+		migrateContract
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return "", err
+	}
+
+	store, err := formatStoreCode(msg.storeCode)
+	if err != nil {
+		return "", err
+	}
+
+	migrate, err := formatMigrateContract(msg.migrateContract)
+	if err != nil {
+		return "", err
+	}
+
+	return store + "\n" + migrate, nil
+}
+
+func formatStoreCode(msg storeCode) (string, error) {
+	if msg.WasmByteCode == "" {
+		return "", errMissingField
+	}
+
+	wasm, err := base64.StdEncoding.DecodeString(msg.WasmByteCode)
+	if err != nil {
+		return "", err
+	}
+
+	checksum, err := wasmChecksum(wasm)
+	if err != nil {
+		return "", err
+	}
+
+	signer := msg.RunAs
+	if signer == "" {
+		signer = msg.Sender
+	}
+	if signer == "" {
+		signer = msg.Authority
+	}
+
+	summary := fmt.Sprintf("Stores wasm code with checksum `%s` (%d bytes)", checksum, len(wasm))
+	if signer != "" {
+		summary += " as " + signer
+	}
+	if expected := codeHash(msg.CodeHash); expected != "" && !strings.EqualFold(expected, checksum) {
+		summary += fmt.Sprintf("\n:warning: the expected checksum of the proposal is `%s`", expected)
+	}
+
+	return summary, nil
+}
+
+func formatMigrateContract(msg migrateContract) (string, error) {
+	if msg.Contract == "" {
+		return "", errMissingField
+	}
+
+	// the code id of a store and migrate is only known once the code is stored
+	code := "the stored code"
+	if msg.CodeID != "" {
+		code = "code " + msg.CodeID
+	}
+
+	summary := fmt.Sprintf("Migrates contract %s to %s", msg.Contract, code)
+	if len(msg.Msg) > 0 {
+		summary += "\nMigrate msg: " + compactJSON(msg.Msg)
+	}
+
+	return summary, nil
+}
+
+// wasmChecksum returns the checksum the chain assigns to the code, which is
+// the SHA-256 of the uncompressed wasm
+func wasmChecksum(wasm []byte) (string, error) {
+	if bytes.HasPrefix(wasm, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(wasm))
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+
+		wasm, err = io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sum := sha256.Sum256(wasm)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// codeHash returns the base64 checksum of a proposal in hex
+func codeHash(hash string) string {
+	decoded, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return hash
+	}
+
+	return hex.EncodeToString(decoded)
+}
@@ -149,6 +149,43 @@ func HitHTTPTarget(ops types.HTTPOptions) (*types.PingResp, error) {
 	return res, nil
 }
 
+// maxPages bounds the pages followed by HitPaginatedTarget
+const maxPages = 100
+
+// HitPaginatedTarget hits the target for every page of a list query. handle
+// gets the response of each page and returns the next_key of its pagination,
+// pages are followed until the next key is empty.
+func HitPaginatedTarget(ops types.HTTPOptions, handle func(resp *types.PingResp) (string, error)) error {
+	queryParams := make(types.QueryParams, len(ops.QueryParams)+1)
+	for key, value := range ops.QueryParams {
+		queryParams[key] = value
+	}
+	ops.QueryParams = queryParams
+
+	for page := 0; page < maxPages; page++ {
+		resp, err := HitHTTPTarget(ops)
+		if err != nil {
+			return err
+		}
+
+		nextKey, err := handle(resp)
+		if err != nil {
+			return err
+		}
+
+		if nextKey == "" {
+			return nil
+		}
+
+		if nextKey == queryParams["pagination.key"] {
+			return fmt.Errorf("%s returned the same page twice", ops.Endpoint)
+		}
+		queryParams["pagination.key"] = nextKey
+	}
+
+	return fmt.Errorf("%s returned more than %d pages", ops.Endpoint, maxPages)
+}
+
 // Adds the Query parameters
 func addQueryParameters(req *http.Request, queryParams types.QueryParams) {
 	q := req.URL.Query()
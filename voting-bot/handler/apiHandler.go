@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/vitwit/authz-apps/voting-bot/database"
@@ -83,3 +85,115 @@ func RetrieveProposalsForAllNetworksHandler(db *database.Sqlitedb) http.HandlerF
 		}
 	}
 }
+
+func GetVoteStatsHandler(db *database.Sqlitedb) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		chainName := mux.Vars(r)["chainName"]
+
+		stats, err := db.GetAlignmentStats(chainName)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while getting vote stats: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(stats)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while encoding vote stats: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func GetProposalOutcomesHandler(db *database.Sqlitedb) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		chainName := mux.Vars(r)["chainName"]
+
+		outcomes, err := db.GetProposalOutcomes(chainName)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while getting proposal outcomes: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(outcomes)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while encoding proposal outcomes: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func GetUpgradesHandler(db *database.Sqlitedb) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		chainName := mux.Vars(r)["chainName"]
+
+		upgrades, err := db.GetUpgrades(chainName, database.UpgradeUpcoming)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while getting upgrades: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(upgrades)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while encoding upgrades: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// GetAuditLogHandler serves the audit log to requests with the token as
+// bearer token. The audit log is not shared with other origins, and is not
+// served at all without a token.
+func GetAuditLogHandler(db *database.Sqlitedb, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.Error(w, "the audit log API is disabled, set api.audit_token in config.toml", http.StatusForbidden)
+			return
+		}
+
+		bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid or missing audit token", http.StatusUnauthorized)
+			return
+		}
+
+		params := r.URL.Query()
+		filter, err := database.NewAuditFilter(params.Get("from"), params.Get("to"), params.Get("user"),
+			params.Get("chain"), params.Get("limit"))
+		if err != nil {
+			http.Error(w, fmt.Errorf("invalid audit log filter: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := db.GetAuditEntries(filter)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while getting audit log: %w", err).Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(entries)
+		if err != nil {
+			http.Error(w, fmt.Errorf("error while encoding audit log: %w", err).Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
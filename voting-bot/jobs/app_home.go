@@ -0,0 +1,301 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+const (
+	// maxAppHomeBlocks is the number of blocks Slack allows in a view
+	maxAppHomeBlocks = 100
+	// maxSectionLength is the length Slack allows for the text of a section
+	maxSectionLength = 3000
+)
+
+// PublishAppHome refreshes the App Home of the users who opened it. The role
+// of every user is checked again with canView, users who may no longer view
+// the overview are removed and shown a notice instead. Users whose role can
+// not be checked are skipped until the next refresh.
+func PublishAppHome(ctx types.Context, canView func(userID string) (bool, error)) {
+	users, err := ctx.Database().GetAppHomeUsers()
+	if err != nil {
+		log.Printf("failed to get App Home users: %v", err)
+		return
+	}
+
+	var blocks []slack.Block
+	for _, user := range users {
+		allowed, err := canView(user)
+		if err != nil {
+			log.Printf("failed to check the App Home access of %s: %v", user, err)
+			continue
+		}
+		if !allowed {
+			if err := RevokeAppHome(ctx, user); err != nil {
+				log.Printf("failed to revoke App Home: %v", err)
+			}
+			continue
+		}
+
+		if blocks == nil {
+			blocks = AppHomeBlocks(ctx)
+		}
+		if err := PublishAppHomeView(ctx, user, blocks); err != nil {
+			log.Printf("failed to publish App Home: %v", err)
+		}
+	}
+}
+
+// RevokeAppHome stops refreshing the App Home of the user and replaces the
+// overview with a notice that it requires the viewer role
+func RevokeAppHome(ctx types.Context, userID string) error {
+	if err := ctx.Database().RemoveAppHomeUser(userID); err != nil {
+		log.Printf("failed to remove App Home user %s: %v", userID, err)
+	}
+
+	return PublishAppHomeView(ctx, userID, []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn",
+			":no_entry: The overview requires the *viewer* role", false, false), nil, nil),
+	})
+}
+
+// PublishAppHomeView publishes the blocks as the App Home of the user
+func PublishAppHomeView(ctx types.Context, userID string, blocks []slack.Block) error {
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+
+	if _, err := ctx.Slacker().APIClient().PublishView(userID, view, ""); err != nil {
+		return fmt.Errorf("failed to publish App Home of %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+// AppHomeBlocks builds the overview shown in the App Home: the active
+// proposals with the votes of the validators, the grantee keys, the last
+// withdrawals and the last runs of the cron jobs
+func AppHomeBlocks(ctx types.Context) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", "Voting bot overview", false, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn",
+			fmt.Sprintf("Updated %s, refreshed whenever a cron job completes", time.Now().UTC().Format(time.RFC822)), false, false)),
+	}
+
+	sections := []struct {
+		title string
+		lines func(types.Context) ([]string, error)
+	}{
+		{"Grantee keys", keyLines},
+		{"Last withdrawals", withdrawalLines},
+		{"Cron jobs", jobRunLines},
+	}
+
+	blocks = append(blocks, appHomeSection("Active proposals", proposalSections(ctx))...)
+	for _, section := range sections {
+		lines, err := section.lines(ctx)
+		if err != nil {
+			log.Printf("failed to get %s for App Home: %v", strings.ToLower(section.title), err)
+			lines = []string{":warning: Failed to load"}
+		}
+
+		blocks = append(blocks, appHomeSection(section.title, []string{strings.Join(lines, "\n")})...)
+	}
+
+	if len(blocks) > maxAppHomeBlocks {
+		blocks = blocks[:maxAppHomeBlocks]
+	}
+
+	return blocks
+}
+
+// appHomeSection returns a titled section of the App Home with a block for
+// each of the texts
+func appHomeSection(title string, texts []string) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*%s*", title), false, false), nil, nil),
+	}
+
+	if len(texts) == 0 {
+		texts = []string{""}
+	}
+
+	for _, text := range texts {
+		if text == "" {
+			text = "_Nothing to show_"
+		}
+		if runes := []rune(text); len(runes) > maxSectionLength {
+			text = string(runes[:maxSectionLength-1]) + "…"
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+	}
+
+	return blocks
+}
+
+// proposalSections lists the active proposals of every registered chain
+// with the time left and the votes of the validators, one text per chain
+func proposalSections(ctx types.Context) []string {
+	db := ctx.Database()
+	vals, err := db.GetValidators()
+	if err != nil {
+		log.Printf("failed to get validators for App Home: %v", err)
+		return []string{":warning: Failed to load"}
+	}
+
+	proposals, err := db.GetActiveProposals()
+	if err != nil {
+		log.Printf("failed to get active proposals for App Home: %v", err)
+		return []string{":warning: Failed to load"}
+	}
+
+	byChain := make(map[string][]database.ActiveProposal)
+	for _, proposal := range proposals {
+		byChain[proposal.ChainName] = append(byChain[proposal.ChainName], proposal)
+	}
+
+	var texts []string
+	for _, chainName := range chainNames(vals) {
+		lines := []string{fmt.Sprintf("*%s*", chainName)}
+		if len(byChain[chainName]) == 0 {
+			lines = append(lines, "No active proposals")
+		}
+
+		for _, proposal := range byChain[chainName] {
+			lines = append(lines, fmt.Sprintf("• *#%s* %s, %s", proposal.ProposalID, proposal.Title, formatVotingEnd(proposal.VotingEndTime)))
+
+			votes, err := db.GetProposalVotes(chainName, proposal.ProposalID)
+			if err != nil {
+				log.Printf("failed to get votes on %s proposal %s: %v", chainName, proposal.ProposalID, err)
+				continue
+			}
+
+			for _, vote := range votes {
+				lines = append(lines, "      "+formatVoteStatus(vote))
+			}
+		}
+
+		texts = append(texts, strings.Join(lines, "\n"))
+	}
+
+	return texts
+}
+
+// formatVotingEnd returns the time left to vote on a proposal
+func formatVotingEnd(votingEndTime string) string {
+	endTime, err := time.Parse(time.RFC3339, votingEndTime)
+	if err != nil {
+		return "end of voting unknown"
+	}
+
+	timeLeft := time.Until(endTime)
+	if timeLeft <= 0 {
+		return "voting ended"
+	}
+
+	return fmt.Sprintf("%s left", FormatTimeLeft(timeLeft))
+}
+
+// formatVoteStatus returns the vote of a validator on a proposal
+func formatVoteStatus(vote database.ProposalVote) string {
+	switch {
+	case vote.VoteOption == "":
+		return fmt.Sprintf(":hourglass: %s has not voted", vote.ValidatorAddress)
+	case vote.Confirmed:
+		return fmt.Sprintf(":white_check_mark: %s voted *%s*", vote.ValidatorAddress, voting.FormatVoteOption(vote.VoteOption))
+	default:
+		return fmt.Sprintf(":ballot_box_with_ballot: %s voted *%s*, not confirmed yet", vote.ValidatorAddress, voting.FormatVoteOption(vote.VoteOption))
+	}
+}
+
+// keyLines lists the grantee keys with their authorization status and last
+// seen balance
+func keyLines(ctx types.Context) ([]string, error) {
+	keys, err := ctx.Database().GetKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, key := range keys {
+		authz := ":x: not authorized"
+		if key.AuthzStatus == "true" {
+			authz = ":white_check_mark: authorized"
+		}
+
+		balance := "balance unknown"
+		if key.Balance != "" {
+			balance = fmt.Sprintf("balance %s as of %s", key.Balance, time.Unix(key.BalanceUpdatedAt, 0).UTC().Format(time.RFC822))
+		}
+
+		lines = append(lines, fmt.Sprintf("• *%s* %s key `%s`, %s, %s", key.ChainName, key.Type, key.GranteeAddress, authz, balance))
+	}
+
+	return lines, nil
+}
+
+// withdrawalLines lists the last withdrawal of the rewards and commission of
+// every validator
+func withdrawalLines(ctx types.Context) ([]string, error) {
+	withdrawals, err := ctx.Database().GetLastWithdrawals()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, withdrawal := range withdrawals {
+		lines = append(lines, fmt.Sprintf("• *%s* %s on %s: rewards %s%s, commission %s%s", withdrawal.ChainID, withdrawal.ValAddr,
+			withdrawal.Date, withdrawal.Rewards, withdrawal.Denom, withdrawal.Commission, withdrawal.Denom))
+	}
+
+	return lines, nil
+}
+
+// jobRunLines lists the last run of every cron job
+func jobRunLines(ctx types.Context) ([]string, error) {
+	runs, err := ctx.Database().GetJobRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, run := range runs {
+		finished := time.Unix(run.FinishedAt, 0).UTC()
+		took := finished.Sub(time.Unix(run.StartedAt, 0))
+		if run.Status == database.JobRunOK {
+			lines = append(lines, fmt.Sprintf(":white_check_mark: *%s* ran at %s in %s", run.Name, finished.Format(time.RFC822), took))
+		} else {
+			lines = append(lines, fmt.Sprintf(":x: *%s* failed at %s: %s", run.Name, finished.Format(time.RFC822), run.Error))
+		}
+	}
+
+	return lines, nil
+}
+
+// storeActiveProposals keeps the active proposals of the chain for the App
+// Home
+func storeActiveProposals(ctx types.Context, chainName string, proposals []ActiveProposalResult) {
+	active := make([]database.ActiveProposal, 0, len(proposals))
+	for _, proposal := range proposals {
+		active = append(active, database.ActiveProposal{
+			ChainName:     chainName,
+			ProposalID:    proposal.ProposalID,
+			Title:         proposal.Title,
+			VotingEndTime: proposal.VotingEndTime,
+		})
+	}
+
+	if err := ctx.Database().SetActiveProposals(chainName, active); err != nil {
+		log.Printf("failed to store active proposals of %s: %v", chainName, err)
+	}
+}
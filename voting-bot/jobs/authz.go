@@ -23,23 +23,40 @@ func SyncAuthzStatus(ctx types.Context) error {
 		return err
 	}
 
+	var errs jobErrors
 	for _, key := range keys {
 		validEndpoint, err := endpoints.GetValidEndpointForChain(key.ChainName)
 		if err != nil {
-			log.Printf("Error in getting valid LCD endpoints for %s chain", key.ChainName)
-			return err
+			errs.addf("Error in getting valid LCD endpoints for %s chain", key.ChainName)
+			continue
 		}
+
+		caps, err := utils.GetChainCapabilities(ctx, key.ChainName, validEndpoint)
+		if err != nil {
+			errs.addf("failed to detect authz support of %s: %v", key.ChainName, err)
+			continue
+		}
+
+		if !caps.Authz {
+			log.Printf("%s does not support authz", key.ChainName)
+			continue
+		}
+
 		for _, val := range validators {
 			if val.ChainName == key.ChainName {
 
-				granter, err := ConvertValAddrToAccAddr(ctx, val.Address, key.ChainName)
+				// a failure for one validator should not stop syncing the
+				// other validators of the chain
+				granter, err := utils.ConvertValAddrToAccAddr(ctx, val.Address, key.ChainName)
 				if err != nil {
-					return err
+					errs.addf("failed to decode validator address %s: %v", val.Address, err)
+					continue
 				}
 
 				hasAuthz, err := utils.HasAuthzGrant(validEndpoint, granter, key.GranteeAddress, MSG_VOTE_TYPEURL_V1BETA1)
 				if err != nil {
-					return err
+					errs.addf("failed to get authz grants of %s for %s: %v", key.GranteeAddress, val.Address, err)
+					continue
 				}
 
 				if hasAuthz {
@@ -50,7 +67,8 @@ func SyncAuthzStatus(ctx types.Context) error {
 
 				hasAuthz, err = utils.HasAuthzGrant(validEndpoint, granter, key.GranteeAddress, MSG_VOTE_TYPEURL_V1)
 				if err != nil {
-					return err
+					errs.addf("failed to get authz grants of %s for %s: %v", key.GranteeAddress, val.Address, err)
+					continue
 				}
 
 				if hasAuthz {
@@ -61,5 +79,6 @@ func SyncAuthzStatus(ctx types.Context) error {
 			}
 		}
 	}
-	return nil
+
+	return errs.err()
 }
@@ -51,6 +51,11 @@ func GetLowBalAccs(ctx types.Context) error {
 			return err
 		}
 
+		// keys whose fees are paid by fee allowances need no funds
+		if hasFeeAllowances(ctx, key) {
+			continue
+		}
+
 		addr := key.GranteeAddress
 		err = AlertOnLowBalance(ctx, grpcEndpoint, addr, baseDenom, coinDecimals, displayDenom)
 		if err != nil {
@@ -86,6 +91,11 @@ func AlertOnLowBalance(ctx types.Context, endpoint, addr, denom string, coinDeci
 		return err
 	}
 
+	amount := sdk.NewDecFromIntWithPrec(balance.Balance.Amount, coinDecimals)
+	if err := ctx.Database().SetKeyBalance(addr, fmt.Sprintf("%.2f%s", amount.MustFloat64(), displayDenom)); err != nil {
+		log.Printf("failed to store balance of %s: %v", addr, err)
+	}
+
 	if balance.Balance.IsLTE(sdk.NewCoin(denom, sdk.NewInt(int64(math.Pow(10, float64(coinDecimals)))))) {
 		err := sendLowBalanceAlerts(ctx, addr, balance.Balance.Amount.Quo(sdk.NewInt(int64(math.Pow(10, float64(coinDecimals))))).String(), displayDenom)
 		if err != nil {
@@ -0,0 +1,128 @@
+package jobs
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+)
+
+// feeAllowanceExpiryWarning is how long before the expiry of a fee allowance
+// it is alerted on
+const feeAllowanceExpiryWarning = 7 * 24 * time.Hour
+
+// AlertOnFeeAllowances alerts on the fee allowances of the validators to the
+// bot keys which expire soon or whose spend limit is almost used up. Like the
+// low balance alert, less than one display unit of the chain denom left is
+// considered low.
+func AlertOnFeeAllowances(ctx types.Context) error {
+	keys, err := ctx.Database().GetKeys()
+	if err != nil {
+		return fmt.Errorf("error while getting keys from db: %v", err)
+	}
+
+	var errs jobErrors
+	now := time.Now().UTC()
+	for _, key := range keys {
+		validEndpoint, err := endpoints.GetValidEndpointForChain(key.ChainName)
+		if err != nil {
+			errs.addf("Error in getting valid LCD endpoints for %s chain", key.ChainName)
+			continue
+		}
+
+		granters, err := getGranters(ctx, key.ChainName)
+		if err != nil {
+			errs.addf("fee allowance job: %v", err)
+			continue
+		}
+
+		for _, granter := range granters {
+			allowance, err := utils.GetFeeAllowance(validEndpoint, granter, key.GranteeAddress)
+			if err != nil {
+				errs.alertf(ctx, "fee allowance job: failed to get fee allowance of %s from %s on %s chain: %v", key.GranteeAddress, granter, key.ChainName, err)
+				continue
+			}
+
+			if allowance == nil {
+				continue
+			}
+
+			if allowance.Expiration != nil {
+				if !now.Before(*allowance.Expiration) {
+					sendPlainAlert(ctx, fmt.Sprintf("Fee allowance of %s key %s from %s on %s chain has expired, fees are paid by the key", key.Type, key.GranteeAddress, granter, key.ChainName))
+					continue
+				}
+
+				if allowance.Expiration.Sub(now) <= feeAllowanceExpiryWarning {
+					sendPlainAlert(ctx, fmt.Sprintf("Fee allowance of %s key %s from %s on %s chain expires at %s", key.Type, key.GranteeAddress, granter, key.ChainName, allowance.Expiration.Format(time.RFC3339)))
+				}
+			}
+
+			if allowance.SpendLimit == nil {
+				continue
+			}
+
+			info, ok := utils.ChainNameToDenomInfo[key.ChainName]
+			if !ok {
+				continue
+			}
+
+			threshold := sdk.NewInt(int64(math.Pow(10, float64(info.DenomUnits))))
+			if left := allowance.SpendLimit.AmountOf(info.BaseDenom); left.LT(threshold) {
+				sendPlainAlert(ctx, fmt.Sprintf("Fee allowance of %s key %s from %s on %s chain is running out\nRemaining spend limit is less than: 1%s", key.Type, key.GranteeAddress, granter, key.ChainName, info.DisplayDenom))
+			}
+		}
+	}
+
+	return errs.err()
+}
+
+// hasFeeAllowances returns true if every validator of the chain has given
+// the key a fee allowance it can execute authz messages with, so the key
+// does not need funds of its own.
+func hasFeeAllowances(ctx types.Context, key database.AuthzKeys) bool {
+	validEndpoint, err := endpoints.GetValidEndpointForChain(key.ChainName)
+	if err != nil {
+		return false
+	}
+
+	granters, err := getGranters(ctx, key.ChainName)
+	if err != nil || len(granters) == 0 {
+		return false
+	}
+
+	now := time.Now().UTC()
+	for _, granter := range granters {
+		allowance, err := utils.GetFeeAllowance(validEndpoint, granter, key.GranteeAddress)
+		if err != nil || allowance == nil || !allowance.Allows(sdk.MsgTypeURL(&authz.MsgExec{}), now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getGranters returns the account addresses of the validators of the chain
+func getGranters(ctx types.Context, chainName string) ([]string, error) {
+	validators, err := ctx.Database().GetChainValidators(chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators of %s chain: %v", chainName, err)
+	}
+
+	var granters []string
+	for _, valAddr := range validators {
+		granter, err := utils.ConvertValAddrToAccAddr(ctx, valAddr, chainName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode validator address %s: %v", valAddr, err)
+		}
+		granters = append(granters, granter)
+	}
+
+	return granters, nil
+}
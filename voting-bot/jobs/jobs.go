@@ -1,10 +1,13 @@
 package jobs
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
+	"github.com/vitwit/authz-apps/voting-bot/database"
 	"github.com/vitwit/authz-apps/voting-bot/types"
 )
 
@@ -12,13 +15,17 @@ import (
 type Cron struct {
 	ctx    types.Context
 	logger *zerolog.Logger
+	// canViewAppHome checks the role of a user before the App Home of the
+	// user is refreshed
+	canViewAppHome func(userID string) (bool, error)
 }
 
 // NewCron sets necessary config and clients to begin cron jobs
-func NewCron(ctx types.Context) *Cron {
+func NewCron(ctx types.Context, canViewAppHome func(userID string) (bool, error)) *Cron {
 	return &Cron{
-		ctx:    ctx,
-		logger: ctx.Logger(),
+		ctx:            ctx,
+		logger:         ctx.Logger(),
+		canViewAppHome: canViewAppHome,
 	}
 }
 
@@ -29,34 +36,161 @@ func (c *Cron) Start() error {
 	cron := cron.New()
 
 	// Everday at 8AM and 8PM
-	_, err := cron.AddFunc("0 8,20 * * *", func() {
-		GetProposals(c.ctx)
-		GetLowBalAccs(c.ctx)
-	})
+	_, err := cron.AddFunc("0 8,20 * * *", c.track("proposals-and-balances", func() error {
+		var errs jobErrors
+		errs.add(GetProposals(c.ctx))
+		errs.add(GetLowBalAccs(c.ctx))
+		return errs.err()
+	}))
 	if err != nil {
 		log.Println("Error while adding Proposals and Low balance accounts alerting cron jobs:", err)
 		return err
 	}
-	_, err = cron.AddFunc("@every 24h", func() {
-		SyncAuthzStatus(c.ctx)
-	})
+
+	// Everyday at 8AM
+	_, err = cron.AddFunc("0 8 * * *", c.track("fee-allowances", func() error {
+		err := AlertOnFeeAllowances(c.ctx)
+		if err != nil {
+			log.Println("Error while alerting on fee allowances:", err)
+		}
+		return err
+	}))
+	if err != nil {
+		log.Println("Error while adding fee allowances alerting cron job:", err)
+		return err
+	}
+	_, err = cron.AddFunc("@every 24h", c.track("sync-authz-status", func() error {
+		return SyncAuthzStatus(c.ctx)
+	}))
 	if err != nil {
 		log.Println("Error while adding Key Authorization syncing cron job:", err)
 		return err
 	}
 
-	_, err = cron.AddFunc("@every 2h", func() {
+	_, err = cron.AddFunc("@every 2h", c.track("withdraw-rewards", func() error {
 		log.Printf("Running withdraw commission CRON job....")
-		if err := Withdraw(c.ctx); err != nil {
+		err := Withdraw(c.ctx)
+		if err != nil {
 			log.Println("Error while adding Key Authorization syncing cron job:", err)
 		}
-	})
+		return err
+	}))
 	if err != nil {
 		log.Println("Error while adding withdraw rewards and commission cron job:", err)
 		return err
 	}
 
+	_, err = cron.AddFunc("@every 5m", c.track("expire-vote-requests", func() error {
+		return ExpireVoteRequests(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding vote requests expiry cron job:", err)
+		return err
+	}
+
+	_, err = cron.AddFunc("@every 1m", c.track("scheduled-votes", func() error {
+		return ExecuteScheduledVotes(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding scheduled votes cron job:", err)
+		return err
+	}
+
+	_, err = cron.AddFunc("@every 10m", c.track("new-proposals", func() error {
+		return AlertOnNewProposals(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding new proposals alerting cron job:", err)
+		return err
+	}
+
+	_, err = cron.AddFunc("@every 5m", c.track("deadline-reminders", func() error {
+		return SendDeadlineReminders(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding deadline reminders cron job:", err)
+		return err
+	}
+
+	_, err = cron.AddFunc("@every 1h", c.track("proposal-outcomes", func() error {
+		return RecordProposalOutcomes(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding proposal outcomes cron job:", err)
+		return err
+	}
+
+	_, err = cron.AddFunc("@every 10m", c.track("upgrades", func() error {
+		return WatchUpgrades(c.ctx)
+	}))
+	if err != nil {
+		log.Println("Error while adding upgrade watcher cron job:", err)
+		return err
+	}
+
 	go cron.Start()
 
 	return nil
 }
+
+// track returns the cron func of the job, which records the run of the job
+// and refreshes the App Home when it completes
+func (c *Cron) track(name string, job func() error) func() {
+	return func() {
+		run := database.JobRun{
+			Name:      name,
+			StartedAt: time.Now().UTC().Unix(),
+			Status:    database.JobRunOK,
+		}
+		if err := job(); err != nil {
+			run.Status = database.JobRunError
+			run.Error = err.Error()
+		}
+		run.FinishedAt = time.Now().UTC().Unix()
+
+		if err := c.ctx.Database().SetJobRun(run); err != nil {
+			log.Printf("failed to store the run of job %s: %v", name, err)
+		}
+
+		PublishAppHome(c.ctx, c.canViewAppHome)
+	}
+}
+
+// jobErrors collects the errors of a job which carries on past failures, so
+// that the run of the job is recorded as failed
+type jobErrors []error
+
+// add records the error, if any
+func (e *jobErrors) add(err error) {
+	if err != nil {
+		*e = append(*e, err)
+	}
+}
+
+// addf logs and records the error
+func (e *jobErrors) addf(format string, args ...interface{}) {
+	err := fmt.Errorf(format, args...)
+	log.Println(err)
+	e.add(err)
+}
+
+// alertf records the error and alerts on it in the channel
+func (e *jobErrors) alertf(ctx types.Context, format string, args ...interface{}) {
+	err := fmt.Errorf(format, args...)
+	e.add(err)
+	if err := sendPlainAlert(ctx, err.Error()); err != nil {
+		log.Printf("failed to send alert: %v", err)
+	}
+}
+
+// err returns the recorded errors as one error, or nil if there are none
+func (e jobErrors) err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	default:
+		return fmt.Errorf("%d errors, first: %v", len(e), e[0])
+	}
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobErrors(t *testing.T) {
+	var errs jobErrors
+	errs.add(nil)
+	assert.NoError(t, errs.err())
+
+	errs.add(errors.New("no active REST endpoint for cosmoshub"))
+	assert.EqualError(t, errs.err(), "no active REST endpoint for cosmoshub")
+
+	errs.addf("failed to get active proposals for %s: %v", "osmosis", errors.New("timeout"))
+	assert.EqualError(t, errs.err(), "2 errors, first: no active REST endpoint for cosmoshub")
+}
@@ -0,0 +1,202 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// defaultVetoThreshold is used when the tally params of a chain can not be
+// queried
+var defaultVetoThreshold = sdk.MustNewDecFromStr("0.334")
+
+// RecordProposalOutcomes revisits the proposals of the vote logs once their
+// voting period is over and stores their final status and tally
+func RecordProposalOutcomes(ctx types.Context) error {
+	pending, err := ctx.Database().GetPendingOutcomes()
+	if err != nil {
+		return fmt.Errorf("failed to get proposals without outcome: %v", err)
+	}
+
+	var errs jobErrors
+	endpointsByChain := make(map[string]string)
+	vetoThresholds := make(map[string]sdk.Dec)
+	for _, p := range pending {
+		endpoint, ok := endpointsByChain[p.ChainName]
+		if !ok {
+			endpoint, err = endpoints.GetValidEndpointForChain(p.ChainName)
+			if err != nil {
+				errs.addf("no active REST endpoint for %s", p.ChainName)
+			}
+			endpointsByChain[p.ChainName] = endpoint
+		}
+		if endpoint == "" {
+			continue
+		}
+
+		isV1 := utils.UseGovV1(ctx, p.ChainName, endpoint)
+		outcome, final, err := getProposalOutcome(isV1, endpoint, p.ChainName, p.ProposalID)
+		if err != nil {
+			errs.addf("failed to get outcome of %s proposal %s: %v", p.ChainName, p.ProposalID, err)
+			continue
+		}
+		if !final {
+			continue
+		}
+
+		if outcome.Outcome == database.OutcomeRejected {
+			vetoThreshold, ok := vetoThresholds[p.ChainName]
+			if !ok {
+				vetoThreshold = getVetoThreshold(isV1, endpoint, p.ChainName)
+				vetoThresholds[p.ChainName] = vetoThreshold
+			}
+
+			if isVetoed(outcome, vetoThreshold) {
+				outcome.Outcome = database.OutcomeVetoed
+			}
+		}
+
+		if err := ctx.Database().AddProposalOutcome(outcome); err != nil {
+			errs.addf("failed to store outcome of %s proposal %s: %v", p.ChainName, p.ProposalID, err)
+			continue
+		}
+		log.Printf("%s proposal %s %s", p.ChainName, p.ProposalID, outcome.Outcome)
+
+		notifyOutcome(ctx, outcome)
+	}
+
+	return errs.err()
+}
+
+// notifyOutcome posts the outcome in the thread of the proposal and shows it
+// as the status of the first alert on the proposal
+func notifyOutcome(ctx types.Context, outcome database.ProposalOutcome) {
+	if err := ctx.Database().SetProposalThreadStatus(outcome.ChainName, outcome.ProposalID, outcome.Outcome); err != nil {
+		log.Printf("failed to update thread of %s proposal %s: %v", outcome.ChainName, outcome.ProposalID, err)
+		return
+	}
+
+	text := fmt.Sprintf(":ballot_box: Proposal %s *%s*\nFinal tally: yes %s, no %s, no with veto %s, abstain %s",
+		outcome.ProposalID, outcome.Outcome, outcome.Yes, outcome.No, outcome.NoWithVeto, outcome.Abstain)
+	if _, err := voting.PostInProposalThread(ctx, outcome.ChainName, outcome.ProposalID, text); err != nil {
+		log.Printf("failed to post outcome of %s proposal %s: %v", outcome.ChainName, outcome.ProposalID, err)
+	}
+}
+
+// getProposalOutcome queries the proposal and returns its outcome, final is
+// false while the proposal is still in its deposit or voting period
+func getProposalOutcome(isV1 bool, endpoint, chainName, proposalID string) (database.ProposalOutcome, bool, error) {
+	outcome := database.ProposalOutcome{
+		ChainName:  chainName,
+		ProposalID: proposalID,
+	}
+
+	version := "v1beta1"
+	if isV1 {
+		version = "v1"
+	}
+
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/gov/" + version + "/proposals/" + proposalID,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return outcome, false, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		outcome.Outcome = database.OutcomeNotFound
+		return outcome, true, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return outcome, false, fmt.Errorf("status code %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var tally types.TallyResult
+	if isV1 {
+		var proposalResp types.ProposalResponse
+		if err := json.Unmarshal(resp.Body, &proposalResp); err != nil {
+			return outcome, false, err
+		}
+
+		outcome.Status = proposalResp.Proposal.Status
+		outcome.VotingEndTime = proposalResp.Proposal.VotingEndTime
+		tally = proposalResp.Proposal.FinalTallyResult
+	} else {
+		var proposalResp types.LegacyProposalResponse
+		if err := json.Unmarshal(resp.Body, &proposalResp); err != nil {
+			return outcome, false, err
+		}
+
+		result := proposalResp.Proposal.FinalTallyResult
+		outcome.Status = proposalResp.Proposal.Status
+		outcome.VotingEndTime = proposalResp.Proposal.VotingEndTime
+		tally = types.TallyResult{Yes: result.Yes, Abstain: result.Abstain, No: result.No, NoWithVeto: result.NoWithVeto}
+	}
+
+	switch strings.TrimPrefix(outcome.Status, "PROPOSAL_STATUS_") {
+	case "PASSED":
+		outcome.Outcome = database.OutcomePassed
+	case "REJECTED":
+		outcome.Outcome = database.OutcomeRejected
+	case "FAILED":
+		outcome.Outcome = database.OutcomeFailed
+	default:
+		return outcome, false, nil
+	}
+
+	outcome.Yes = parseInt(tally.Yes, tally.YesCount).String()
+	outcome.Abstain = parseInt(tally.Abstain, tally.AbstainCount).String()
+	outcome.No = parseInt(tally.No, tally.NoCount).String()
+	outcome.NoWithVeto = parseInt(tally.NoWithVeto, tally.NoWithVetoCount).String()
+
+	return outcome, true, nil
+}
+
+func getVetoThreshold(isV1 bool, endpoint, chainName string) sdk.Dec {
+	version := "v1beta1"
+	if isV1 {
+		version = "v1"
+	}
+
+	var params types.TallyParamsResponse
+	if err := getJSON(endpoint+"/cosmos/gov/"+version+"/params/tallying", &params); err != nil {
+		log.Printf("failed to get tally params of %s: %v", chainName, err)
+		return defaultVetoThreshold
+	}
+
+	threshold, err := sdk.NewDecFromStr(params.TallyParams.VetoThreshold)
+	if err != nil {
+		return defaultVetoThreshold
+	}
+
+	return threshold
+}
+
+// isVetoed returns true if the no with veto votes of the final tally exceed
+// the veto threshold. The chain reports vetoed proposals as rejected.
+func isVetoed(outcome database.ProposalOutcome, vetoThreshold sdk.Dec) bool {
+	tally := Tally{
+		Yes:        parseInt(outcome.Yes),
+		Abstain:    parseInt(outcome.Abstain),
+		No:         parseInt(outcome.No),
+		NoWithVeto: parseInt(outcome.NoWithVeto),
+	}
+
+	total := tally.Total()
+	if !total.IsPositive() {
+		return false
+	}
+
+	return sdk.NewDecFromInt(tally.NoWithVeto).QuoInt(total).GT(vetoThreshold)
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+)
+
+func TestIsVetoed(t *testing.T) {
+	threshold := sdk.MustNewDecFromStr("0.334")
+
+	tests := []struct {
+		name    string
+		outcome database.ProposalOutcome
+		want    bool
+	}{
+		{
+			name:    "veto above threshold",
+			outcome: database.ProposalOutcome{Yes: "50", No: "10", NoWithVeto: "40"},
+			want:    true,
+		},
+		{
+			name:    "veto at threshold",
+			outcome: database.ProposalOutcome{Yes: "666", NoWithVeto: "334"},
+		},
+		{
+			name:    "veto below threshold",
+			outcome: database.ProposalOutcome{Yes: "70", No: "30", NoWithVeto: "20"},
+		},
+		{
+			name:    "abstain counts towards the total",
+			outcome: database.ProposalOutcome{Abstain: "60", NoWithVeto: "40"},
+			want:    true,
+		},
+		{
+			name:    "only veto votes",
+			outcome: database.ProposalOutcome{NoWithVeto: "1"},
+			want:    true,
+		},
+		{
+			name:    "no votes",
+			outcome: database.ProposalOutcome{Yes: "0", Abstain: "0", No: "0", NoWithVeto: "0"},
+		},
+		{
+			name:    "empty tally",
+			outcome: database.ProposalOutcome{},
+		},
+		{
+			name:    "invalid tally",
+			outcome: database.ProposalOutcome{Yes: "abc", NoWithVeto: "xyz"},
+		},
+		{
+			name:    "large tally",
+			outcome: database.ProposalOutcome{Yes: "100000000000000000000000", NoWithVeto: "60000000000000000000000"},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isVetoed(tt.outcome, threshold))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/vitwit/authz-apps/voting-bot/decoder"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+type (
+	// ProposalDetails holds a proposal along with its current tally and the
+	// votes of the registered validators
+	ProposalDetails struct {
+		ChainName       string
+		ProposalID      string
+		Title           string
+		Summary         string
+		Status          string
+		Proposer        string
+		Messages        []decoder.Message
+		TotalDeposit    string
+		SubmitTime      string
+		DepositEndTime  string
+		VotingStartTime string
+		VotingEndTime   string
+		Tally           Tally
+		TallyParams     TallyParams
+		BondedTokens    sdk.Int
+		// Votes of the registered validators of the chain, an empty vote
+		// means the validator has not voted
+		Votes map[string]string
+	}
+
+	Tally struct {
+		Yes        sdk.Int
+		Abstain    sdk.Int
+		No         sdk.Int
+		NoWithVeto sdk.Int
+	}
+
+	TallyParams struct {
+		Quorum        sdk.Dec
+		Threshold     sdk.Dec
+		VetoThreshold sdk.Dec
+	}
+)
+
+// GetProposalDetails fetches the proposal, its tally and the tally params of
+// the chain, and the votes of the registered validators on it
+func GetProposalDetails(ctx types.Context, chainName, proposalID string) (ProposalDetails, error) {
+	endpoint, err := endpoints.GetValidEndpointForChain(chainName)
+	if err != nil {
+		return ProposalDetails{}, fmt.Errorf("no active REST endpoint for %s: %v", chainName, err)
+	}
+
+	isV1 := utils.UseGovV1(ctx, chainName, endpoint)
+	details := ProposalDetails{
+		ChainName:  chainName,
+		ProposalID: proposalID,
+		Votes:      make(map[string]string),
+	}
+
+	if isV1 {
+		err = getV1ProposalDetails(endpoint, &details)
+	} else {
+		err = getLegacyProposalDetails(endpoint, &details)
+	}
+	if err != nil {
+		return ProposalDetails{}, err
+	}
+
+	version := "v1beta1"
+	if isV1 {
+		version = "v1"
+	}
+
+	var tally types.TallyResponse
+	if err := getJSON(endpoint+"/cosmos/gov/"+version+"/proposals/"+proposalID+"/tally", &tally); err != nil {
+		return ProposalDetails{}, fmt.Errorf("failed to get tally of proposal %s: %v", proposalID, err)
+	}
+	details.Tally = Tally{
+		Yes:        parseInt(tally.Tally.Yes, tally.Tally.YesCount),
+		Abstain:    parseInt(tally.Tally.Abstain, tally.Tally.AbstainCount),
+		No:         parseInt(tally.Tally.No, tally.Tally.NoCount),
+		NoWithVeto: parseInt(tally.Tally.NoWithVeto, tally.Tally.NoWithVetoCount),
+	}
+
+	var params types.TallyParamsResponse
+	if err := getJSON(endpoint+"/cosmos/gov/"+version+"/params/tallying", &params); err != nil {
+		return ProposalDetails{}, fmt.Errorf("failed to get tally params of %s: %v", chainName, err)
+	}
+	details.TallyParams = TallyParams{
+		Quorum:        parseDec(params.TallyParams.Quorum),
+		Threshold:     parseDec(params.TallyParams.Threshold),
+		VetoThreshold: parseDec(params.TallyParams.VetoThreshold),
+	}
+
+	var pool types.StakingPoolResponse
+	if err := getJSON(endpoint+"/cosmos/staking/v1beta1/pool", &pool); err != nil {
+		return ProposalDetails{}, fmt.Errorf("failed to get bonded tokens of %s: %v", chainName, err)
+	}
+	details.BondedTokens = parseInt(pool.Pool.BondedTokens)
+
+	validators, err := ctx.Database().GetChainValidators(chainName)
+	if err != nil {
+		return ProposalDetails{}, fmt.Errorf("failed to get validators of %s: %v", chainName, err)
+	}
+
+	for _, valAddr := range validators {
+		vote, err := voting.GetValidatorVoteOption(ctx, isV1, chainName, endpoint, proposalID, valAddr)
+		if err != nil {
+			log.Printf("failed to get vote of %s on %s proposal %s: %v", valAddr, chainName, proposalID, err)
+			vote = "unknown"
+		}
+		details.Votes[valAddr] = vote
+	}
+
+	return details, nil
+}
+
+func getV1ProposalDetails(endpoint string, details *ProposalDetails) error {
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/gov/v1/proposals/" + details.ProposalID,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proposal %s not found on %s: %s", details.ProposalID, details.ChainName, resp.Body)
+	}
+
+	var proposalResp types.ProposalResponse
+	if err := json.Unmarshal(resp.Body, &proposalResp); err != nil {
+		return err
+	}
+
+	proposal := proposalResp.Proposal
+	details.Title = proposal.Title
+	details.Summary = proposal.Summary
+	if details.Title == "" {
+		details.Title, _ = getTitleFromProposal(proposal)
+	}
+
+	if details.Summary == "" {
+		meta, err := getMetadata(proposal.Metadata)
+		if err != nil {
+			log.Printf("failed to get metadata of %s proposal %s: %v", details.ChainName, details.ProposalID, err)
+		}
+		details.Summary = meta.Summary
+	}
+
+	for _, message := range proposal.Messages {
+		if details.Summary == "" && message.Content.Description != "" {
+			details.Summary = message.Content.Description
+		}
+
+		details.Messages = append(details.Messages, decoder.Decode(message.Raw))
+	}
+
+	details.Status = proposal.Status
+	details.Proposer = proposal.Proposer
+	details.TotalDeposit = formatCoins(proposal.TotalDeposit)
+	details.SubmitTime = proposal.SubmitTime
+	details.DepositEndTime = proposal.DepositEndTime
+	details.VotingStartTime = proposal.VotingStartTime
+	details.VotingEndTime = proposal.VotingEndTime
+
+	return nil
+}
+
+func getLegacyProposalDetails(endpoint string, details *ProposalDetails) error {
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/gov/v1beta1/proposals/" + details.ProposalID,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proposal %s not found on %s: %s", details.ProposalID, details.ChainName, resp.Body)
+	}
+
+	var proposalResp types.LegacyProposalResponse
+	if err := json.Unmarshal(resp.Body, &proposalResp); err != nil {
+		return err
+	}
+
+	proposal := proposalResp.Proposal
+	details.Title = proposal.Content.Title
+	details.Summary = proposal.Content.Description
+	details.Messages = []decoder.Message{decoder.Decode(proposal.Content.Raw)}
+
+	var deposit []types.Coin
+	for _, coin := range proposal.TotalDeposit {
+		deposit = append(deposit, types.Coin{Denom: coin.Denom, Amount: coin.Amount})
+	}
+
+	details.Status = proposal.Status
+	details.TotalDeposit = formatCoins(deposit)
+	details.SubmitTime = proposal.SubmitTime
+	details.DepositEndTime = proposal.DepositEndTime
+	details.VotingStartTime = proposal.VotingStartTime
+	details.VotingEndTime = proposal.VotingEndTime
+
+	return nil
+}
+
+// getJSON queries the endpoint and decodes the response into out
+func getJSON(url string, out interface{}) error {
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: url,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	return json.Unmarshal(resp.Body, out)
+}
+
+// parseInt parses the first non-empty value, invalid values are zero
+func parseInt(values ...string) sdk.Int {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		if i, ok := sdk.NewIntFromString(value); ok {
+			return i
+		}
+	}
+
+	return sdk.ZeroInt()
+}
+
+func parseDec(value string) sdk.Dec {
+	dec, err := sdk.NewDecFromStr(value)
+	if err != nil {
+		return sdk.ZeroDec()
+	}
+
+	return dec
+}
+
+func formatCoins(coins []types.Coin) string {
+	var out []string
+	for _, coin := range coins {
+		out = append(out, coin.Amount+coin.Denom)
+	}
+
+	return strings.Join(out, ", ")
+}
+
+// Total returns the voting power of all votes
+func (t Tally) Total() sdk.Int {
+	return t.Yes.Add(t.Abstain).Add(t.No).Add(t.NoWithVeto)
+}
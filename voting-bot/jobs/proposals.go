@@ -1,8 +1,6 @@
 package jobs
 
 import (
-	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,10 +10,13 @@ import (
 	"time"
 
 	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/config"
 	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/decoder"
 	"github.com/vitwit/authz-apps/voting-bot/endpoints"
 	"github.com/vitwit/authz-apps/voting-bot/types"
 	"github.com/vitwit/authz-apps/voting-bot/utils"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
 )
 
 type (
@@ -24,119 +25,185 @@ type (
 		pTitle        string
 		pID           string
 		votingEndTime string
+		messages      []decoder.Message
+		// voting rule suggesting a vote on the proposal, if one matched
+		rule *config.VotingRuleConfig
 	}
 )
 
 // Gets proposals from the Registered chains and validators
-func GetProposals(ctx types.Context) {
+func GetProposals(ctx types.Context) error {
 	vals, err := ctx.Database().GetValidators()
 	if err != nil {
-		log.Fatalf("Error while getting validators: %v", err)
+		return fmt.Errorf("error while getting validators: %v", err)
 	}
 
-	networksMap := make(map[string]bool)
-	var networks []string
-	for _, val := range vals {
-		if !networksMap[val.ChainName] {
-			networks = append(networks, val.ChainName)
-			networksMap[val.ChainName] = true
-		}
+	return alertOnProposals(ctx, chainNames(vals), vals, true)
+}
+
+// ListProposals alerts on all proposals the registered validators have not
+// voted on, including the ones alerted on recently
+func ListProposals(ctx types.Context) {
+	vals, err := ctx.Database().GetValidators()
+	if err != nil {
+		log.Printf("Error while getting validators: %v", err)
+		return
 	}
 
-	alertOnProposals(ctx, networks, vals)
+	if err := alertOnProposals(ctx, chainNames(vals), vals, false); err != nil {
+		log.Printf("failed to list proposals: %v", err)
+	}
 }
 
-// Alerts on Active Proposals
-func alertOnProposals(ctx types.Context, networks []string, validators []database.Validator) {
-	for _, val := range validators {
-		endpoint, err := endpoints.GetValidEndpointForChain(val.ChainName)
+// AlertOnNewProposals polls the active proposals of the registered chains and
+// alerts once on the proposals which were not seen before. Unlike
+// GetProposals it does not query the votes of the validators, so it can run
+// often. Voting rules are applied to the new proposals, the vote of a
+// validator is only queried when an auto vote rule matches.
+func AlertOnNewProposals(ctx types.Context) error {
+	vals, err := ctx.Database().GetValidators()
+	if err != nil {
+		return fmt.Errorf("error while getting validators: %v", err)
+	}
+
+	var errs jobErrors
+	for _, network := range chainNames(vals) {
+		endpoint, err := endpoints.GetValidEndpointForChain(network)
 		if err != nil {
-			log.Printf("no active REST endpoint for %s", val.ChainName)
-			sendPlainAlert(ctx, fmt.Sprintf("No active %s endpoint available for %s", "REST", val.ChainName))
+			errs.addf("no active REST endpoint for %s", network)
 			continue
 		}
 
-		var missedProposals []MissedProposal
-		if utils.GovV1Support[val.ChainName]["govv1_enabled"] {
+		isV1 := utils.UseGovV1(ctx, network, endpoint)
+		proposals, err := GetActiveProposals(ctx, isV1, endpoint)
+		if err != nil {
+			errs.addf("failed to get active proposals for %s: %v", network, err)
+			continue
+		}
 
-			proposals, err := GetActiveProposals(ctx, true, endpoint)
-			if err != nil {
-				log.Printf("failed to get active proposal for %s", val.ChainName)
-				sendPlainAlert(ctx, fmt.Sprintf("failed to get active proposals for chain %s: %v", val.ChainName, err))
+		storeActiveProposals(ctx, network, proposals)
+
+		var newProposals []MissedProposal
+		for _, val := range vals {
+			if val.ChainName != network {
 				continue
 			}
 
 			for _, proposal := range proposals {
-				if err := ctx.Database().AddLog(val.ChainName, proposal.Title, proposal.ProposalID, ""); err != nil {
-					fmt.Printf("failed to store vote logs: %v", err)
-				}
-
-				vote, err := GetValidatorVoteOption(ctx, true, val.ChainName, endpoint, proposal.ProposalID, val.Address)
+				isNew, err := ctx.Database().AddProposalAlert(val.ChainName, val.Address, proposal.ProposalID)
 				if err != nil {
-					log.Printf("failed to get validator vote for %s", val.ChainName)
-					sendPlainAlert(ctx, fmt.Sprintf("failed to get validator vote fo %s: %v", val.ChainName, err))
+					errs.addf("failed to store alert state of %s proposal %s: %v", network, proposal.ProposalID, err)
+					continue
+				}
+				if !isNew {
 					continue
 				}
 
-				if vote == "" {
-					missedProposals = append(missedProposals, MissedProposal{
-						accAddr:       val.Address,
-						pTitle:        proposal.Title,
-						pID:           proposal.ProposalID,
-						votingEndTime: proposal.VotingEndTime,
-					})
-				} else {
-					if err := ctx.Database().UpdateVoteLog(val.ChainName, proposal.ProposalID, vote); err != nil {
-						fmt.Printf("failed to update vote log: %v", err)
-					}
+				if err := ctx.Database().AddLog(val.ChainName, val.Address, proposal.Title, proposal.ProposalID, ""); err != nil {
+					log.Printf("failed to store vote logs: %v", err)
+				}
+
+				rule, voted := applyNewProposalRule(ctx, isV1, endpoint, network, val.Address, proposal)
+				if voted {
+					continue
 				}
+
+				newProposals = append(newProposals, MissedProposal{
+					accAddr:       val.Address,
+					pTitle:        proposal.Title,
+					pID:           proposal.ProposalID,
+					votingEndTime: proposal.VotingEndTime,
+					messages:      proposal.Messages,
+					rule:          rule,
+				})
 			}
+		}
 
-		} else {
-			proposals, err := GetActiveProposals(ctx, false, endpoint)
-			if err != nil {
-				log.Printf("failed to get active proposal for %s", val.ChainName)
-				sendPlainAlert(ctx, fmt.Sprintf("failed to get active proposals for chain %s: %v", val.ChainName, err))
+		errs.add(sendProposalAlerts(ctx, network, fmt.Sprintf("New proposals on %s", network), newProposals))
+	}
+
+	return errs.err()
+}
+
+// Alerts on Active Proposals. With dedup set, proposals alerted on within
+// the reminder interval are left out.
+func alertOnProposals(ctx types.Context, networks []string, validators []database.Validator, dedup bool) error {
+	var errs jobErrors
+	for _, network := range networks {
+		endpoint, err := endpoints.GetValidEndpointForChain(network)
+		if err != nil {
+			errs.addf("no active REST endpoint for %s", network)
+			sendPlainAlert(ctx, fmt.Sprintf("No active %s endpoint available for %s", "REST", network))
+			continue
+		}
+
+		isV1 := utils.UseGovV1(ctx, network, endpoint)
+		proposals, err := GetActiveProposals(ctx, isV1, endpoint)
+		if err != nil {
+			errs.addf("failed to get active proposal for %s: %v", network, err)
+			sendPlainAlert(ctx, fmt.Sprintf("failed to get active proposals for chain %s: %v", network, err))
+			continue
+		}
+
+		storeActiveProposals(ctx, network, proposals)
+
+		var missedProposals, newProposals, reminders []MissedProposal
+		for _, val := range validators {
+			if val.ChainName != network {
 				continue
 			}
 
 			for _, proposal := range proposals {
-				if err := ctx.Database().AddLog(val.ChainName, proposal.Title, proposal.ProposalID, ""); err != nil {
+				if err := ctx.Database().AddLog(val.ChainName, val.Address, proposal.Title, proposal.ProposalID, ""); err != nil {
 					fmt.Printf("failed to store vote logs: %v", err)
 				}
 
-				vote, err := GetValidatorVoteOption(ctx, false, val.ChainName, endpoint, proposal.ProposalID, val.Address)
+				vote, err := voting.GetValidatorVoteOption(ctx, isV1, val.ChainName, endpoint, proposal.ProposalID, val.Address)
 				if err != nil {
-					log.Printf("failed to get validator vote for %s", val.ChainName)
+					errs.addf("failed to get validator vote for %s: %v", val.ChainName, err)
 					sendPlainAlert(ctx, fmt.Sprintf("failed to get validator vote fo %s: %v", val.ChainName, err))
 					continue
 				}
 
 				if vote == "" {
-					missedProposals = append(missedProposals, MissedProposal{
+					rule, voted := applyVotingRule(ctx, val.ChainName, val.Address, proposal)
+					if voted {
+						continue
+					}
+
+					missed := MissedProposal{
 						accAddr:       val.Address,
 						pTitle:        proposal.Title,
 						pID:           proposal.ProposalID,
 						votingEndTime: proposal.VotingEndTime,
-					})
+						messages:      proposal.Messages,
+						rule:          rule,
+					}
+					missedProposals = append(missedProposals, missed)
+
+					isNew, remind := alertState(ctx, val.ChainName, val.Address, proposal.ProposalID)
+					if isNew {
+						newProposals = append(newProposals, missed)
+					} else if remind || !dedup {
+						reminders = append(reminders, missed)
+					}
 				} else {
-					if err := ctx.Database().UpdateVoteLog(val.ChainName, proposal.ProposalID, vote); err != nil {
+					if err := ctx.Database().UpdateVoteLog(val.ChainName, val.Address, proposal.ProposalID, vote); err != nil {
 						fmt.Printf("failed to update vote log: %v", err)
 					}
 				}
 			}
-
 		}
 
-		log.Println("Network name = ", val.ChainName)
+		log.Println("Network name = ", network)
 		log.Println("Missed proposals = ", len(missedProposals))
-		if len(missedProposals) > 0 {
-			err = sendVotingPeriodProposalAlerts(ctx, val.ChainName, missedProposals)
-			if err != nil {
-				log.Printf("error on sending voting period proposals alert: %v", err)
-			}
-		}
+		errs.add(sendProposalAlerts(ctx, network, fmt.Sprintf("New proposals on %s", network), newProposals))
+		errs.add(sendProposalAlerts(ctx, network, fmt.Sprintf(" %s ", network), reminders))
+
+		scheduleFallbackVotes(ctx, network, missedProposals)
 	}
+
+	return errs.err()
 }
 
 func sendPlainAlert(ctx types.Context, msg string) error {
@@ -159,17 +226,186 @@ func sendPlainAlert(ctx types.Context, msg string) error {
 	return nil
 }
 
+// minReminderInterval is the least time between alerts on a proposal the
+// validator has not voted on, so a new proposal alert is not repeated by the
+// next reminder
+const minReminderInterval = 6 * time.Hour
+
+// alertState stores the proposal as seen for the validator. isNew is true if
+// it was not seen before, remind is true if no alert on it was sent within
+// the reminder interval.
+func alertState(ctx types.Context, chainName, valAddr, proposalID string) (isNew, remind bool) {
+	isNew, err := ctx.Database().AddProposalAlert(chainName, valAddr, proposalID)
+	if err != nil {
+		log.Printf("failed to store alert state of %s proposal %s: %v", chainName, proposalID, err)
+		return false, true
+	}
+	if isNew {
+		return true, false
+	}
+
+	alert, _, err := ctx.Database().GetProposalAlert(chainName, valAddr, proposalID)
+	if err != nil {
+		log.Printf("failed to get alert state of %s proposal %s: %v", chainName, proposalID, err)
+		return false, true
+	}
+
+	return false, time.Since(time.Unix(alert.LastAlerted, 0)) >= minReminderInterval
+}
+
+// sendProposalAlerts sends the alert of the proposals under the heading and
+// records them as alerted
+func sendProposalAlerts(ctx types.Context, chainName, heading string, proposals []MissedProposal) error {
+	if len(proposals) == 0 {
+		return nil
+	}
+
+	if err := sendVotingPeriodProposalAlerts(ctx, heading, chainName, proposals); err != nil {
+		log.Printf("error on sending voting period proposals alert: %v", err)
+		return fmt.Errorf("failed to send %s proposal alerts: %v", chainName, err)
+	}
+
+	for _, p := range proposals {
+		if err := ctx.Database().SetProposalAlerted(chainName, p.accAddr, p.pID); err != nil {
+			log.Printf("failed to store alert state of %s proposal %s: %v", chainName, p.pID, err)
+		}
+	}
+
+	return nil
+}
+
+// maxValidatorsPerAlert keeps an alert, which takes up to 4 blocks per
+// validator, within the 50 blocks of a Slack message
+const maxValidatorsPerAlert = 10
+
 // sendVotingPeriodProposalAlerts which send alerts of voting period proposals
-func sendVotingPeriodProposalAlerts(ctx types.Context, chainName string, proposals []MissedProposal) error {
+func sendVotingPeriodProposalAlerts(ctx types.Context, heading, chainName string, proposals []MissedProposal) error {
+	for _, validators := range groupByProposal(proposals) {
+		if err := sendProposalAlert(ctx, heading, chainName, validators); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupByProposal splits the proposals of the validators by proposal, in the
+// order the proposals are first seen
+func groupByProposal(proposals []MissedProposal) [][]MissedProposal {
+	var groups [][]MissedProposal
+	index := make(map[string]int)
+	for _, p := range proposals {
+		i, ok := index[p.pID]
+		if !ok {
+			i = len(groups)
+			index[p.pID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], p)
+	}
+
+	return groups
+}
+
+// sendProposalAlert alerts on a proposal the validators have not voted on.
+// The first alert on the proposal starts the thread of the proposal, later
+// alerts are posted in the thread and update the status of the first alert.
+func sendProposalAlert(ctx types.Context, heading, chainName string, validators []MissedProposal) error {
 	api := ctx.Slacker().APIClient()
-	var blocks []slack.Block
+	pID := validators[0].pID
+	thread, found, err := ctx.Database().GetProposalThread(chainName, pID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread of %s proposal %s: %v", chainName, pID, err)
+	}
+
+	for start := 0; start < len(validators); start += maxValidatorsPerAlert {
+		end := start + maxValidatorsPerAlert
+		if end > len(validators) {
+			end = len(validators)
+		}
 
+		if !found {
+			thread, err = startProposalThread(ctx, heading, chainName, validators[start:end])
+			if err != nil {
+				return err
+			}
+			found = true
+			continue
+		}
+
+		blocks := []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", ":bell: Not voted yet", false, false), nil, nil),
+		}
+		blocks = append(blocks, proposalAlertBlocks(chainName, validators[start:end])...)
+		_, _, err := api.PostMessage(
+			thread.ChannelID,
+			slack.MsgOptionBlocks(blocks...),
+			slack.MsgOptionTS(thread.ThreadTS),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := voting.UpdateProposalThread(ctx, chainName, pID); err != nil {
+		log.Printf("failed to update thread of %s proposal %s: %v", chainName, pID, err)
+	}
+
+	return nil
+}
+
+// startProposalThread posts the first alert on the proposal and stores it as
+// the thread of the proposal, with a prompt to discuss the vote in it
+func startProposalThread(ctx types.Context, heading, chainName string, validators []MissedProposal) (database.ProposalThread, error) {
+	p := validators[0]
+	thread := database.ProposalThread{
+		ChainName:     chainName,
+		ProposalID:    p.pID,
+		Title:         p.pTitle,
+		VotingEndTime: p.votingEndTime,
+		Status:        database.ThreadVotingPeriod,
+	}
+
+	votes, err := ctx.Database().GetProposalVotes(chainName, p.pID)
+	if err != nil {
+		log.Printf("failed to get votes on %s proposal %s: %v", chainName, p.pID, err)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", heading, false, false)),
+	}
+	blocks = append(blocks, proposalAlertBlocks(chainName, validators)...)
+	blocks = append(blocks, voting.ProposalStatusBlock(thread, votes))
+
+	api := ctx.Slacker().APIClient()
+	thread.ChannelID, thread.ThreadTS, err = api.PostMessage(
+		ctx.Config().Slack.ChannelID,
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return database.ProposalThread{}, err
+	}
+
+	if _, err := ctx.Database().AddProposalThread(thread); err != nil {
+		log.Printf("failed to store thread of %s proposal %s: %v", chainName, p.pID, err)
+	}
+
+	prompt := fmt.Sprintf(":speech_balloon: Discuss the vote on %s proposal %s here. Reminders, votes and the outcome of the proposal are posted in this thread.",
+		chainName, p.pID)
+	_, _, err = api.PostMessage(thread.ChannelID, slack.MsgOptionText(prompt, false), slack.MsgOptionTS(thread.ThreadTS))
+	if err != nil {
+		log.Printf("failed to post discussion prompt of %s proposal %s: %v", chainName, p.pID, err)
+	}
+
+	return thread, nil
+}
+
+// proposalAlertBlocks shows the proposal with buttons to vote on it for each
+// of the validators
+func proposalAlertBlocks(chainName string, proposals []MissedProposal) []slack.Block {
+	var blocks []slack.Block
 	for _, p := range proposals {
 		endTime, _ := time.Parse(time.RFC3339, p.votingEndTime)
-		daysLeft := int(time.Until(endTime).Hours() / 24)
-		if daysLeft == 0 {
-			daysLeft = 1
-		}
 		blocks = append(blocks, slack.NewSectionBlock(
 			slack.NewTextBlockObject(
 				"mrkdwn",
@@ -183,84 +419,154 @@ func sendVotingPeriodProposalAlerts(ctx types.Context, chainName string, proposa
 			mintscanName = newName
 		}
 		fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Proposal Id*\n *<https://mintscan.io/%s/proposals/%s| %s >* ", mintscanName, p.pID, p.pID), false, false))
-		fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Voting ends in* \n %d days ", daysLeft), false, false))
+		fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Voting ends in* \n %s ", FormatTimeLeft(time.Until(endTime))), false, false))
 		fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Validator* \n%s", p.accAddr), false, false))
+		if p.rule != nil {
+			fields = append(fields, slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Suggested vote* \n%s (rule %s) %s\n`vote %s %s %s <gasPrices>`",
+				p.rule.VoteOption, p.rule.Name, p.rule.Rationale, p.accAddr, p.pID, p.rule.VoteOption), false, false))
+		}
 		blocks = append(blocks, slack.NewSectionBlock(nil, fields, nil, slack.SectionBlockOptionBlockID("")))
+		if summary := summarizeMessages(p.messages); summary != "" {
+			blocks = append(blocks, slack.NewSectionBlock(
+				slack.NewTextBlockObject("mrkdwn", summary, false, false),
+				nil, nil))
+		}
+		blocks = append(blocks, voteButtons(p.accAddr, p.pID))
 	}
-	attachment := []slack.Block{
-		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", fmt.Sprintf(" %s ", chainName), false, false)),
+
+	return blocks
+}
+
+// maxAlertSummaryLength keeps the summary of the messages within the text
+// limit of a Slack section
+const maxAlertSummaryLength = 2900
+
+// maxAlertRawLength is the length the raw JSON of a message without a
+// decoder is cut to, so that the summary shows several of them
+const maxAlertRawLength = 600
+
+// summarizeMessages lists the decoded messages of a proposal for its alert.
+// Messages without a decoder are listed by type with their raw JSON.
+func summarizeMessages(messages []decoder.Message) string {
+	var lines []string
+	for _, message := range messages {
+		switch {
+		case message.Decoded:
+			lines = append(lines, "• "+message.Summary)
+		case message.Summary != "":
+			raw := []rune(message.Summary)
+			if len(raw) > maxAlertRawLength {
+				raw = append(raw[:maxAlertRawLength], '…')
+			}
+			if message.Type == "" {
+				lines = append(lines, fmt.Sprintf("• ```%s```", string(raw)))
+			} else {
+				lines = append(lines, fmt.Sprintf("• `%s`\n```%s```", message.Type, string(raw)))
+			}
+		case message.Type != "":
+			lines = append(lines, fmt.Sprintf("• `%s`", message.Type))
+		}
 	}
-	attachment = append(attachment, blocks...)
 
-	_, _, err := api.PostMessage(
-		ctx.Config().Slack.ChannelID,
-		slack.MsgOptionBlocks(attachment...),
-	)
-	if err != nil {
-		return err
+	if len(lines) == 0 {
+		return ""
 	}
 
-	return nil
+	summary := []rune("*Messages*\n" + strings.Join(lines, "\n"))
+	if len(summary) > maxAlertSummaryLength {
+		summary = append(summary[:maxAlertSummaryLength], '…')
+	}
+
+	return string(summary)
 }
 
 type ActiveProposalResult struct {
 	ProposalID    string
 	Title         string
 	VotingEndTime string
+	Proposer      string
+	// Type URLs of the proposal messages, including the content of legacy
+	// proposals
+	MessageTypes []string
+	// Messages are the decoded messages, or the content of legacy proposals
+	Messages []decoder.Message
 }
 
 func GetActiveProposals(ctx types.Context, isV1 bool, restEndpoint string) ([]ActiveProposalResult, error) {
 	if isV1 {
-		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		var proposals []types.Proposal
+		err := endpoints.HitPaginatedTarget(types.HTTPOptions{
 			Endpoint:    restEndpoint + "/cosmos/gov/v1/proposals",
 			Method:      http.MethodGet,
 			QueryParams: types.QueryParams{"proposal_status": "2"},
+		}, func(resp *types.PingResp) (string, error) {
+			var page types.Proposals
+			if err := json.Unmarshal(resp.Body, &page); err != nil {
+				return "", err
+			}
+
+			proposals = append(proposals, page.Proposals...)
+			return page.Pagination.NextKey, nil
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		var proposals types.Proposals
-		if err := json.Unmarshal(resp.Body, &proposals); err != nil {
-			return nil, err
-		}
-
 		var result []ActiveProposalResult
-		for _, proposal := range proposals.Proposals {
+		for _, proposal := range proposals {
 			title, err := getTitleFromProposal(proposal)
 			if err != nil {
 				title = "Unknown title"
 			}
 
+			var messageTypes []string
+			var messages []decoder.Message
+			for _, message := range proposal.Messages {
+				messageTypes = append(messageTypes, message.Type)
+				if message.Content.Type != "" {
+					messageTypes = append(messageTypes, message.Content.Type)
+				}
+				messages = append(messages, decoder.Decode(message.Raw))
+			}
+
 			result = append(result, ActiveProposalResult{
 				ProposalID:    proposal.ID,
 				Title:         title,
 				VotingEndTime: proposal.VotingEndTime,
+				Proposer:      proposal.Proposer,
+				MessageTypes:  messageTypes,
+				Messages:      messages,
 			})
 		}
 
 		return result, nil
 	} else {
-		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		var proposals []types.LegacyProposal
+		err := endpoints.HitPaginatedTarget(types.HTTPOptions{
 			Endpoint:    restEndpoint + "/cosmos/gov/v1beta1/proposals",
 			Method:      http.MethodGet,
 			QueryParams: types.QueryParams{"proposal_status": "2"},
+		}, func(resp *types.PingResp) (string, error) {
+			var page types.LegacyProposals
+			if err := json.Unmarshal(resp.Body, &page); err != nil {
+				return "", err
+			}
+
+			proposals = append(proposals, page.Proposals...)
+			return page.Pagination.NextKey, nil
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		var proposals types.LegacyProposals
-		if err := json.Unmarshal(resp.Body, &proposals); err != nil {
-			return nil, err
-		}
-
 		var result []ActiveProposalResult
-		for _, proposal := range proposals.Proposals {
+		for _, proposal := range proposals {
 			result = append(result, ActiveProposalResult{
 				ProposalID:    proposal.ProposalID,
 				Title:         proposal.Content.Title,
 				VotingEndTime: proposal.VotingEndTime,
+				MessageTypes:  []string{proposal.Content.Type},
+				Messages:      []decoder.Message{decoder.Decode(proposal.Content.Raw)},
 			})
 		}
 
@@ -268,69 +574,52 @@ func GetActiveProposals(ctx types.Context, isV1 bool, restEndpoint string) ([]Ac
 	}
 }
 
-func GetValidatorVoteOption(ctx types.Context, isV1 bool, chainName, restEndpoint, proposalID, validatorAddress string) (string, error) {
-	accAddrString, err := ConvertValAddrToAccAddr(ctx, validatorAddress, chainName)
+// Gets the end of the voting period of the proposal on the chain
+func GetProposalVotingEndTime(ctx types.Context, chainName, proposalID string) (time.Time, error) {
+	endpoint, err := endpoints.GetValidEndpointForChain(chainName)
 	if err != nil {
-		return "", err
+		return time.Time{}, fmt.Errorf("no active REST endpoint for %s: %v", chainName, err)
 	}
 
-	if isV1 {
+	var votingEndTime string
+	if utils.UseGovV1(ctx, chainName, endpoint) {
 		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
-			Endpoint: restEndpoint + "/cosmos/gov/v1/proposals/" + proposalID + "/votes/" + accAddrString,
+			Endpoint: endpoint + "/cosmos/gov/v1/proposals/" + proposalID,
 			Method:   http.MethodGet,
 		})
 		if err != nil {
-			return "", err
+			return time.Time{}, err
 		}
 
-		var vote types.VoteResponse
-		if err := json.Unmarshal(resp.Body, &vote); err != nil {
-			return "", err
+		var proposal types.ProposalResponse
+		if err := json.Unmarshal(resp.Body, &proposal); err != nil {
+			return time.Time{}, err
 		}
 
-		if len(vote.Vote.Options) == 0 {
-			return "", nil
-		}
-
-		if len(vote.Vote.Options) == 1 {
-			return vote.Vote.Options[0].Option, nil
-		}
-
-		var voteResult string
-		for _, option := range vote.Vote.Options {
-			voteResult += fmt.Sprintf("%s-%s", option.Option, option.Weight)
-		}
-
-		return voteResult, nil
-
+		votingEndTime = proposal.Proposal.VotingEndTime
 	} else {
 		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
-			Endpoint: restEndpoint + "/cosmos/gov/v1beta1/proposals/" + proposalID + "/votes/" + accAddrString,
+			Endpoint: endpoint + "/cosmos/gov/v1beta1/proposals/" + proposalID,
 			Method:   http.MethodGet,
 		})
 		if err != nil {
-			return "", err
-		}
-		var vote types.LegacyVoteResponse
-		if err := json.Unmarshal(resp.Body, &vote); err != nil {
-			return "", err
-		}
-
-		if len(vote.Vote.Options) == 0 {
-			return "", nil
+			return time.Time{}, err
 		}
 
-		if len(vote.Vote.Options) == 1 {
-			return vote.Vote.Options[0].Option, nil
+		var proposal types.LegacyProposalResponse
+		if err := json.Unmarshal(resp.Body, &proposal); err != nil {
+			return time.Time{}, err
 		}
 
-		var voteResult string
-		for _, option := range vote.Vote.Options {
-			voteResult += fmt.Sprintf("%s-%s", option.Option, option.Weight)
-		}
+		votingEndTime = proposal.Proposal.VotingEndTime
+	}
 
-		return voteResult, nil
+	endTime, err := time.Parse(time.RFC3339, votingEndTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("proposal %s on %s has no voting end time: %v", proposalID, chainName, err)
 	}
+
+	return endTime, nil
 }
 
 func getTitleFromProposal(proposal types.Proposal) (string, error) {
@@ -352,34 +641,40 @@ func getTitleFromProposal(proposal types.Proposal) (string, error) {
 }
 
 func getMetadataTitle(metadata interface{}) (string, error) {
+	meta, err := getMetadata(metadata)
+	return meta.Title, err
+}
+
+// getMetadata reads the metadata of a gov v1 proposal, which is either JSON
+// or an IPFS link to it
+func getMetadata(metadata interface{}) (types.Metadata, error) {
 	switch metadata.(type) {
 	case string:
-		return getTitleFromString(metadata.(string))
+		return getMetadataFromString(metadata.(string))
 	case types.Metadata:
-		return metadata.(types.Metadata).Title, nil
+		return metadata.(types.Metadata), nil
 	}
 
-	return "", nil
+	return types.Metadata{}, nil
 }
 
-func getTitleFromString(metadataStr string) (string, error) {
+func getMetadataFromString(metadataStr string) (types.Metadata, error) {
 	if metadataStr == "" {
-		return "", nil
+		return types.Metadata{}, nil
 	}
 
 	if strings.Contains(metadataStr, "ipfs://") {
-		ipfsTitle, err := fetchMetadataFromIPFS(metadataStr[7:])
-		return ipfsTitle, err
+		return fetchMetadataFromIPFS(metadataStr[7:])
 	}
 
-	// Otherwise, unmarshal and get the title
+	// Otherwise, unmarshal the metadata
 	var meta types.Metadata
 	err := json.Unmarshal([]byte(metadataStr), &meta)
 	if err != nil {
-		return "", err
+		return types.Metadata{}, err
 	}
 
-	return meta.Title, nil
+	return meta, nil
 }
 
 type GovMetadata struct {
@@ -387,46 +682,23 @@ type GovMetadata struct {
 	Summary string `json:"summary"`
 }
 
-func fetchMetadataFromIPFS(ipfsLink string) (string, error) {
+func fetchMetadataFromIPFS(ipfsLink string) (types.Metadata, error) {
 	resp, err := http.Get("https://ipfs.io/ipfs/" + ipfsLink)
 	if err != nil {
-		return "", err
+		return types.Metadata{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return types.Metadata{}, err
 	}
 
 	var meta types.Metadata
 	err = json.Unmarshal(body, &meta)
 	if err != nil {
-		return "", err
-	}
-
-	return meta.Title, nil
-}
-
-func ConvertValAddrToAccAddr(ctx types.Context, valAddr, chainName string) (string, error) {
-	chainInfo, err := ctx.ChainRegistry().GetChain(context.Background(), chainName)
-	if err != nil {
-		return "", err
-	}
-
-	done := utils.SetBech32Prefixes(chainInfo)
-	addr, err := utils.ValAddressFromBech32(valAddr)
-	if err != nil {
-		done()
-		return "", err
-	}
-
-	accAddr, err := utils.AccAddressFromHexUnsafe(hex.EncodeToString(addr.Bytes()))
-	if err != nil {
-		return "", err
+		return types.Metadata{}, err
 	}
 
-	accAddrString := accAddr.String()
-	done()
-	return accAddrString, nil
+	return meta, nil
 }
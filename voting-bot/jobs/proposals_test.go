@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitwit/authz-apps/voting-bot/decoder"
+)
+
+func TestSummarizeMessages(t *testing.T) {
+	assert.Equal(t, "", summarizeMessages(nil))
+
+	summary := summarizeMessages([]decoder.Message{
+		{Type: "/cosmos.bank.v1beta1.MsgSend", Summary: "Send 1atom", Decoded: true},
+		{Type: "/custom.v1.MsgFoo", Summary: "{\n  \"foo\": \"bar\"\n}"},
+		{Type: "/custom.v1.MsgEmpty"},
+		{Summary: "not json"},
+	})
+	assert.Equal(t, "*Messages*\n• Send 1atom\n• `/custom.v1.MsgFoo`\n```{\n  \"foo\": \"bar\"\n}```\n• `/custom.v1.MsgEmpty`\n• ```not json```", summary)
+
+	// raw JSON is cut so that the code block stays closed
+	summary = summarizeMessages([]decoder.Message{{Type: "/custom.v1.MsgFoo", Summary: strings.Repeat("x", 2*maxAlertRawLength)}})
+	assert.True(t, strings.HasSuffix(summary, "…```"))
+	assert.Less(t, len([]rune(summary)), maxAlertSummaryLength)
+
+	// the summary as a whole is cut to the limit of a section
+	var messages []decoder.Message
+	for i := 0; i < 10; i++ {
+		messages = append(messages, decoder.Message{Type: "/custom.v1.MsgFoo", Summary: strings.Repeat("x", maxAlertRawLength)})
+	}
+	assert.Len(t, []rune(summarizeMessages(messages)), maxAlertSummaryLength+1)
+}
@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// SendDeadlineReminders reminds of proposals which validators have not voted
+// on as the end of the voting period nears. A reminder is sent once for each
+// configured threshold the time left drops below, the on call user is
+// mentioned in reminders within the on call window.
+func SendDeadlineReminders(ctx types.Context) error {
+	vals, err := ctx.Database().GetValidators()
+	if err != nil {
+		return fmt.Errorf("error while getting validators: %v", err)
+	}
+
+	var errs jobErrors
+	reminders := ctx.Config().Reminders
+	for _, network := range chainNames(vals) {
+		endpoint, err := endpoints.GetValidEndpointForChain(network)
+		if err != nil {
+			errs.addf("no active REST endpoint for %s", network)
+			continue
+		}
+
+		isV1 := utils.UseGovV1(ctx, network, endpoint)
+		proposals, err := GetActiveProposals(ctx, isV1, endpoint)
+		if err != nil {
+			errs.addf("failed to get active proposals for %s: %v", network, err)
+			continue
+		}
+
+		for _, proposal := range proposals {
+			endTime, err := time.Parse(time.RFC3339, proposal.VotingEndTime)
+			if err != nil {
+				continue
+			}
+
+			timeLeft := time.Until(endTime)
+			threshold, ok := reminderThreshold(reminders.Thresholds, timeLeft)
+			if !ok {
+				continue
+			}
+
+			sent, err := ctx.Database().HasProposalReminder(network, proposal.ProposalID, threshold)
+			if err != nil {
+				errs.addf("failed to get reminders of %s proposal %s: %v", network, proposal.ProposalID, err)
+				continue
+			}
+			if sent {
+				continue
+			}
+
+			var unvoted []string
+			complete := true
+			for _, val := range vals {
+				if val.ChainName != network {
+					continue
+				}
+
+				vote, err := voting.GetValidatorVoteOption(ctx, isV1, network, endpoint, proposal.ProposalID, val.Address)
+				if err != nil {
+					errs.addf("failed to get vote of %s on %s proposal %s: %v", val.Address, network, proposal.ProposalID, err)
+					complete = false
+					continue
+				}
+
+				if vote == "" {
+					unvoted = append(unvoted, val.Address)
+				}
+			}
+
+			// the reminder is tried again on the next run if a vote is unknown
+			if !complete {
+				continue
+			}
+
+			if err := ctx.Database().AddProposalReminder(network, proposal.ProposalID, threshold); err != nil {
+				errs.addf("failed to store reminder of %s proposal %s: %v", network, proposal.ProposalID, err)
+			}
+
+			if len(unvoted) == 0 {
+				continue
+			}
+
+			mention := ""
+			if timeLeft <= reminders.OnCallWindow {
+				mention = reminders.OnCallMention()
+			}
+
+			if err := sendDeadlineReminder(ctx, network, proposal, timeLeft, unvoted, mention); err != nil {
+				errs.addf("error on sending deadline reminder: %v", err)
+			}
+		}
+	}
+
+	return errs.err()
+}
+
+// reminderThreshold returns the smallest threshold which is not below the
+// time left, or false if the time left is above all thresholds or the voting
+// period has ended.
+func reminderThreshold(thresholds []time.Duration, timeLeft time.Duration) (time.Duration, bool) {
+	if timeLeft <= 0 {
+		return 0, false
+	}
+
+	sorted := append([]time.Duration(nil), thresholds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, threshold := range sorted {
+		if timeLeft <= threshold {
+			return threshold, true
+		}
+	}
+
+	return 0, false
+}
+
+// sendDeadlineReminder sends the reminder of a proposal the validators have
+// not voted on
+func sendDeadlineReminder(ctx types.Context, chainName string, proposal ActiveProposalResult, timeLeft time.Duration,
+	validators []string, mention string,
+) error {
+	mintscanName := chainName
+	if newName, ok := utils.RegisrtyNameToMintscanName[chainName]; ok {
+		mintscanName = newName
+	}
+
+	text := fmt.Sprintf(":alarm_clock: Voting on %s proposal *<https://mintscan.io/%s/proposals/%s|%s>* %s ends in *%s*",
+		chainName, mintscanName, proposal.ProposalID, proposal.ProposalID, proposal.Title, FormatTimeLeft(timeLeft))
+	if mention != "" {
+		text = mention + " " + text
+	}
+	text += fmt.Sprintf("\nNot voted yet: %s", strings.Join(validators, ", "))
+
+	posted, err := voting.PostInProposalThread(ctx, chainName, proposal.ProposalID, text)
+	if posted {
+		return err
+	}
+	if err != nil {
+		log.Printf("failed to get thread of %s proposal %s: %v", chainName, proposal.ProposalID, err)
+	}
+
+	_, _, err = ctx.Slacker().APIClient().PostMessage(
+		ctx.Config().Slack.ChannelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil)),
+	)
+	return err
+}
+
+// FormatTimeLeft formats the time left to vote in days and hours, or in
+// hours and minutes within the last day
+func FormatTimeLeft(d time.Duration) string {
+	if d <= 0 {
+		return "0h 0m"
+	}
+
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// chainNames returns the chains of the validators
+func chainNames(vals []database.Validator) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, val := range vals {
+		if !seen[val.ChainName] {
+			names = append(names, val.ChainName)
+			seen[val.ChainName] = true
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminderThreshold(t *testing.T) {
+	thresholds := []time.Duration{24 * time.Hour, time.Hour, 6 * time.Hour}
+
+	tests := []struct {
+		name       string
+		thresholds []time.Duration
+		timeLeft   time.Duration
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "above all thresholds", thresholds: thresholds, timeLeft: 48 * time.Hour},
+		{name: "just above the largest", thresholds: thresholds, timeLeft: 24*time.Hour + time.Second},
+		{name: "at the largest", thresholds: thresholds, timeLeft: 24 * time.Hour, want: 24 * time.Hour, wantOK: true},
+		{name: "between thresholds", thresholds: thresholds, timeLeft: 12 * time.Hour, want: 24 * time.Hour, wantOK: true},
+		{name: "below the middle", thresholds: thresholds, timeLeft: 5 * time.Hour, want: 6 * time.Hour, wantOK: true},
+		{name: "below the smallest", thresholds: thresholds, timeLeft: 10 * time.Minute, want: time.Hour, wantOK: true},
+		{name: "voting ended", thresholds: thresholds, timeLeft: 0},
+		{name: "voting ended long ago", thresholds: thresholds, timeLeft: -time.Hour},
+		{name: "no thresholds", timeLeft: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, ok := reminderThreshold(tt.thresholds, tt.timeLeft)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, threshold)
+		})
+	}
+
+	// the configured thresholds are not reordered
+	assert.Equal(t, []time.Duration{24 * time.Hour, time.Hour, 6 * time.Hour}, thresholds)
+}
+
+func TestFormatTimeLeft(t *testing.T) {
+	tests := []struct {
+		timeLeft time.Duration
+		want     string
+	}{
+		{timeLeft: 3*24*time.Hour + 5*time.Hour + 59*time.Minute, want: "3d 5h"},
+		{timeLeft: 24 * time.Hour, want: "1d 0h"},
+		{timeLeft: 23*time.Hour + 59*time.Minute + 59*time.Second, want: "23h 59m"},
+		{timeLeft: 90 * time.Minute, want: "1h 30m"},
+		{timeLeft: 59 * time.Second, want: "0h 0m"},
+		{timeLeft: 0, want: "0h 0m"},
+		{timeLeft: -time.Hour, want: "0h 0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatTimeLeft(tt.timeLeft))
+		})
+	}
+}
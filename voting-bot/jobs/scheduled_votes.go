@@ -0,0 +1,274 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// Executes the scheduled votes whose time has come. If approvals are
+// required, the vote request created when the vote was scheduled must be
+// approved by then. Fallback votes are approved by the config and are skipped
+// if the validator has voted.
+func ExecuteScheduledVotes(ctx types.Context) error {
+	db := ctx.Database()
+	votes, err := db.GetDueScheduledVotes(time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled votes: %v", err)
+	}
+
+	var errs jobErrors
+	for _, vote := range votes {
+		// votes cancelled in the meantime are skipped
+		updated, err := db.UpdateScheduledVoteStatus(vote.ID, database.ScheduledVotePending, database.ScheduledVoteSubmitted)
+		if err != nil {
+			errs.addf("failed to update scheduled vote %d: %v", vote.ID, err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+
+		req := database.VoteRequest{
+			Target:      vote.Target,
+			ProposalID:  vote.ProposalID,
+			VoteType:    vote.VoteType,
+			VoteOption:  vote.VoteOption,
+			GasPrices:   vote.GasPrices,
+			Memo:        vote.Memo,
+			Metadata:    vote.Metadata,
+			RequestedBy: vote.ScheduledBy,
+		}
+
+		chainName := targetChainName(ctx, vote.Target)
+		responseWriter := voting.NewAuditWriter(voting.ProposalThreadWriter(ctx, chainName, vote.ProposalID))
+		entry := database.AuditEntry{
+			ChainName: chainName,
+			Params: fmt.Sprintf("#%d %s %s on proposal %s for %s", vote.ID, vote.VoteType, vote.VoteOption,
+				vote.ProposalID, vote.Target),
+		}
+		if vote.Fallback {
+			entry.Command = "fallback-vote"
+			entry, err := execFallbackVote(ctx, vote, req, responseWriter, entry)
+			errs.add(err)
+			voting.AddAuditEntry(ctx, entry)
+			continue
+		}
+
+		entry.Command, entry.UserID = "scheduled-vote", vote.ScheduledBy
+		if err := checkScheduledVoteApproval(ctx, vote); err != nil {
+			if _, err := db.UpdateScheduledVoteStatus(vote.ID, database.ScheduledVoteSubmitted, database.ScheduledVoteSkipped); err != nil {
+				log.Printf("failed to update scheduled vote %d: %v", vote.ID, err)
+			}
+			responseWriter.ReportError(err)
+			voting.AddAuditEntry(ctx, responseWriter.Entry(entry))
+			continue
+		}
+
+		responseWriter.Reply(fmt.Sprintf("Executing scheduled vote *#%d* of <@%s>: %s %s on %s proposal %s",
+			vote.ID, vote.ScheduledBy, vote.VoteType, vote.VoteOption, vote.Target, vote.ProposalID))
+		status, details := database.VoteRequestExecuted, voting.DescribeVoteRequest(req)
+		if err := voting.ExecVoteRequest(ctx, req, false, responseWriter); err != nil {
+			responseWriter.ReportError(err)
+			errs.add(fmt.Errorf("scheduled vote %d failed: %v", vote.ID, err))
+			status, details = database.VoteRequestFailed, err.Error()
+		}
+
+		if vote.VoteRequestID != 0 {
+			if _, err := db.UpdateVoteRequestStatus(vote.VoteRequestID, database.VoteRequestApproved, status); err != nil {
+				log.Printf("failed to update vote request %d: %v", vote.VoteRequestID, err)
+			}
+			voting.AuditVoteRequest(ctx, vote.VoteRequestID, "", status, details)
+		}
+		voting.AddAuditEntry(ctx, responseWriter.Entry(entry))
+	}
+
+	return errs.err()
+}
+
+// checkScheduledVoteApproval returns an error if the scheduled vote requires
+// approvals and its vote request was not approved. A request which is still
+// pending at the scheduled time expires.
+func checkScheduledVoteApproval(ctx types.Context, vote database.ScheduledVote) error {
+	approval := ctx.Config().Approval
+	if vote.VoteRequestID == 0 {
+		if approval.RequiredApprovals > 0 {
+			return fmt.Errorf("scheduled vote #%d is not executed, it was scheduled without a vote request and approvals are required", vote.ID)
+		}
+		return nil
+	}
+
+	db := ctx.Database()
+	req, err := db.GetVoteRequest(vote.VoteRequestID)
+	if err != nil {
+		return fmt.Errorf("scheduled vote #%d is not executed, failed to get vote request %d: %v", vote.ID, vote.VoteRequestID, err)
+	}
+
+	switch req.Status {
+	case database.VoteRequestApproved:
+		return nil
+	case database.VoteRequestPending:
+		updated, err := db.UpdateVoteRequestStatus(req.ID, database.VoteRequestPending, database.VoteRequestExpired)
+		if err != nil {
+			log.Printf("failed to update vote request %d: %v", req.ID, err)
+		}
+		if updated {
+			voting.AuditVoteRequest(ctx, req.ID, "", database.VoteRequestExpired, "not approved before the scheduled time")
+		}
+		return fmt.Errorf("scheduled vote #%d is not executed, vote request %d was not approved in time (%d/%d approvals)",
+			vote.ID, req.ID, req.Approvals, approval.RequiredApprovals)
+	default:
+		return fmt.Errorf("scheduled vote #%d is not executed, vote request %d is %s", vote.ID, req.ID, req.Status)
+	}
+}
+
+// targetChainName returns the chain of a vote target, which is a chain name
+// or a validator address
+func targetChainName(ctx types.Context, target string) string {
+	if ctx.Database().HasValidator(target) {
+		if val, err := ctx.Database().GetValidator(target); err == nil {
+			return val.ChainName
+		}
+	}
+
+	return target
+}
+
+// execFallbackVote casts the fallback vote if the validator still has not
+// voted on the proposal and returns the audit entry of the vote, and the
+// error if the vote was not cast
+func execFallbackVote(ctx types.Context, vote database.ScheduledVote, req database.VoteRequest, responseWriter *voting.AuditWriter,
+	entry database.AuditEntry,
+) (database.AuditEntry, error) {
+	db := ctx.Database()
+	val, err := db.GetValidator(vote.Target)
+	if err != nil {
+		log.Printf("failed to get validator %s: %v", vote.Target, err)
+		err = fmt.Errorf("fallback vote #%d is not executed, %s is not registered anymore", vote.ID, vote.Target)
+		responseWriter.ReportError(err)
+		return responseWriter.Entry(entry), err
+	}
+
+	endpoint, err := endpoints.GetValidEndpointForChain(val.ChainName)
+	if err != nil {
+		err = fmt.Errorf("fallback vote #%d is not executed, no active REST endpoint for %s", vote.ID, val.ChainName)
+		responseWriter.ReportError(err)
+		return responseWriter.Entry(entry), err
+	}
+
+	isV1 := utils.UseGovV1(ctx, val.ChainName, endpoint)
+	option, err := voting.GetValidatorVoteOption(ctx, isV1, val.ChainName, endpoint, vote.ProposalID, val.Address)
+	if err != nil {
+		err = fmt.Errorf("fallback vote #%d is not executed, failed to get vote of %s: %v", vote.ID, val.Address, err)
+		responseWriter.ReportError(err)
+		return responseWriter.Entry(entry), err
+	}
+
+	if option != "" {
+		if _, err := db.UpdateScheduledVoteStatus(vote.ID, database.ScheduledVoteSubmitted, database.ScheduledVoteSkipped); err != nil {
+			log.Printf("failed to update scheduled vote %d: %v", vote.ID, err)
+		}
+		log.Printf("fallback vote %d skipped, %s voted %s on %s proposal %s", vote.ID, val.Address, option, val.ChainName, vote.ProposalID)
+		entry.Result = fmt.Sprintf("%s: %s voted %s", database.AuditResultSkipped, val.Address, option)
+		return entry, nil
+	}
+
+	responseWriter.Reply(fmt.Sprintf("Executing fallback vote *#%d*: %s on %s proposal %s for %s, which has not voted yet",
+		vote.ID, vote.VoteOption, val.ChainName, vote.ProposalID, val.Address))
+	if err := voting.ExecVoteRequest(ctx, req, false, responseWriter); err != nil {
+		responseWriter.ReportError(err)
+		return responseWriter.Entry(entry), fmt.Errorf("fallback vote %d failed: %v", vote.ID, err)
+	}
+
+	return responseWriter.Entry(entry), nil
+}
+
+// minFallbackNotice is the least time given to cancel a fallback vote after
+// it is announced
+const minFallbackNotice = 30 * time.Minute
+
+// scheduleFallbackVotes schedules the fallback vote of the chain on the
+// proposals the validators have not voted on and announces them. A fallback
+// vote is scheduled once per validator and proposal, so that a cancelled
+// vote is not scheduled again.
+func scheduleFallbackVotes(ctx types.Context, chainName string, proposals []MissedProposal) {
+	fallback, ok := ctx.Config().FallbackVote(chainName)
+	if !ok {
+		return
+	}
+
+	if err := voting.ValidateVoteOption(fallback.VoteOption); err != nil {
+		sendPlainAlert(ctx, fmt.Sprintf("invalid fallback vote of %s: %v", chainName, err))
+		return
+	}
+
+	db := ctx.Database()
+	for _, p := range proposals {
+		exists, err := db.HasFallbackVote(p.accAddr, p.pID)
+		if err != nil {
+			log.Printf("failed to get fallback vote of %s on proposal %s: %v", p.accAddr, p.pID, err)
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		endTime, err := time.Parse(time.RFC3339, p.votingEndTime)
+		if err != nil {
+			log.Printf("invalid voting end time of %s proposal %s: %v", chainName, p.pID, err)
+			continue
+		}
+
+		at := endTime.Add(-fallback.BeforeEnd)
+		if earliest := time.Now().Add(minFallbackNotice); at.Before(earliest) {
+			at = earliest
+		}
+		if !at.Before(endTime) {
+			log.Printf("too little time left to schedule fallback vote of %s on %s proposal %s", p.accAddr, chainName, p.pID)
+			continue
+		}
+
+		vote := database.ScheduledVote{
+			Target:      p.accAddr,
+			ProposalID:  p.pID,
+			VoteType:    database.VoteTypeSingle,
+			VoteOption:  fallback.VoteOption,
+			GasPrices:   fallback.GasPrices,
+			ScheduledBy: "fallback policy",
+			ExecuteAt:   at.Unix(),
+			BeforeEnd:   fallback.BeforeEnd,
+			Fallback:    true,
+		}
+		id, err := db.AddScheduledVote(vote)
+		if err != nil {
+			log.Printf("failed to store fallback vote of %s on proposal %s: %v", p.accAddr, p.pID, err)
+			continue
+		}
+
+		voting.AddAuditEntry(ctx, database.AuditEntry{
+			Command:   "schedule-fallback-vote",
+			ChainName: chainName,
+			Params:    fmt.Sprintf("#%d %s on proposal %s for %s at %s", id, vote.VoteOption, p.pID, p.accAddr, at.UTC().Format(time.RFC3339)),
+			Result:    database.AuditResultOK,
+		})
+
+		msg := fmt.Sprintf("Fallback vote #%d scheduled: %s on %s proposal %s for %s at %s unless the validator votes before. Cancel it with cancel-scheduled-vote %d",
+			id, vote.VoteOption, chainName, p.pID, p.accAddr, at.UTC().Format(time.RFC822), id)
+		posted, err := voting.PostInProposalThread(ctx, chainName, p.pID, msg)
+		if err != nil {
+			log.Printf("failed to post fallback vote in thread: %v", err)
+		}
+		if posted {
+			continue
+		}
+		if err := sendPlainAlert(ctx, msg); err != nil {
+			log.Printf("failed to send fallback vote alert: %v", err)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+)
+
+const (
+	// blockTimeWindow is the number of recent blocks the block time is
+	// averaged over
+	blockTimeWindow = 1000
+	// defaultBlockTime is used when the block time can not be estimated
+	defaultBlockTime = 6 * time.Second
+	// passedProposalsLimit is the number of latest passed proposals searched
+	// for upgrades
+	passedProposalsLimit = "20"
+)
+
+// blockHeader is the height and time of a block
+type blockHeader struct {
+	Height int64
+	Time   time.Time
+}
+
+// WatchUpgrades tracks the software upgrades of the chains of the registered
+// validators. The current plan of a chain is authoritative, passed upgrade
+// proposals are used when it can not be queried. Newly planned upgrades,
+// cancelled and reached upgrades are announced, and countdown reminders are
+// sent as the estimated upgrade time nears.
+func WatchUpgrades(ctx types.Context) error {
+	vals, err := ctx.Database().GetValidators()
+	if err != nil {
+		return fmt.Errorf("error while getting validators: %v", err)
+	}
+
+	var errs jobErrors
+	for _, network := range chainNames(vals) {
+		if err := watchChainUpgrades(ctx, network); err != nil {
+			errs.addf("failed to watch upgrades of %s: %v", network, err)
+		}
+	}
+
+	return errs.err()
+}
+
+func watchChainUpgrades(ctx types.Context, chainName string) error {
+	db := ctx.Database()
+
+	endpoint, err := endpoints.GetValidEndpointForChain(chainName)
+	if err != nil {
+		return fmt.Errorf("no active REST endpoint: %v", err)
+	}
+
+	latest, err := getBlockHeader(endpoint, "latest")
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %v", err)
+	}
+
+	plans, proposals, planErr := getPlannedUpgrades(ctx, chainName, endpoint)
+	if planErr != nil {
+		log.Printf("failed to get current plan of %s, using passed proposals: %v", chainName, planErr)
+	}
+
+	blockTime := estimateBlockTime(endpoint, latest)
+	for _, plan := range plans {
+		if plan.Height <= latest.Height {
+			continue
+		}
+
+		stored, found, err := db.GetUpgrade(chainName, plan.Name)
+		if err != nil {
+			return err
+		}
+
+		plan.ProposalID = proposals[plan.Name]
+		if plan.ProposalID == "" {
+			plan.ProposalID = stored.ProposalID
+		}
+		plan.EstimatedTime = estimateHeightTime(latest, blockTime, plan.Height).Unix()
+		plan.Status = database.UpgradeUpcoming
+
+		if err := db.SetUpgrade(plan); err != nil {
+			return err
+		}
+
+		if found && stored.Status == database.UpgradeUpcoming && stored.Height == plan.Height {
+			continue
+		}
+
+		postUpgradeAlert(ctx, fmt.Sprintf(":package: Software upgrade *%s* of %s planned at height %d, %s",
+			plan.Name, chainName, plan.Height, formatUpgradeETA(plan)))
+
+		// the announcement stands in for the reminder of the current threshold
+		timeLeft := time.Until(time.Unix(plan.EstimatedTime, 0))
+		if threshold, ok := reminderThreshold(ctx.Config().Upgrades.Thresholds, timeLeft); ok {
+			if err := db.AddUpgradeReminder(chainName, plan.Name, threshold); err != nil {
+				log.Printf("failed to store reminder of %s upgrade %s: %v", chainName, plan.Name, err)
+			}
+		}
+	}
+
+	upcoming, err := db.GetUpgrades(chainName, database.UpgradeUpcoming)
+	if err != nil {
+		return err
+	}
+
+	for _, upgrade := range upcoming {
+		_, planned := plans[upgrade.Name]
+		switch {
+		case upgrade.Height <= latest.Height:
+			upgrade.Status = database.UpgradeDone
+			postUpgradeAlert(ctx, fmt.Sprintf(":white_check_mark: %s reached the height %d of upgrade *%s*",
+				chainName, upgrade.Height, upgrade.Name))
+		// without the current plan, upgrades of passed proposals which were
+		// cancelled or replaced by later proposals are no longer planned
+		case !planned && (planErr == nil || proposals[upgrade.Name] != ""):
+			upgrade.Status = database.UpgradeCancelled
+			postUpgradeAlert(ctx, fmt.Sprintf(":x: Software upgrade *%s* of %s at height %d is no longer planned",
+				upgrade.Name, chainName, upgrade.Height))
+		default:
+			sendUpgradeReminder(ctx, upgrade)
+			continue
+		}
+
+		if err := db.SetUpgrade(upgrade); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPlannedUpgrades returns the upgrades planned on the chain by their name,
+// along with the ids of the passed proposals by upgrade name. If the current
+// plan can not be queried, the upgrade of the latest passed proposals is
+// returned with the error, unless it was cancelled.
+func getPlannedUpgrades(ctx types.Context, chainName, endpoint string) (map[string]database.Upgrade, map[string]string, error) {
+	passed, err := getPassedUpgradeProposals(ctx, chainName, endpoint)
+	if err != nil {
+		log.Printf("failed to get passed upgrade proposals of %s: %v", chainName, err)
+	}
+
+	proposals := make(map[string]string)
+	for _, upgrade := range passed {
+		proposals[upgrade.Name] = upgrade.ProposalID
+	}
+
+	plans := make(map[string]database.Upgrade)
+	var currentPlan types.CurrentPlanResponse
+	if err := getJSON(endpoint+"/cosmos/upgrade/v1beta1/current_plan", &currentPlan); err != nil {
+		for _, upgrade := range passed {
+			if upgrade.Status == database.UpgradeUpcoming {
+				plans[upgrade.Name] = upgrade
+			}
+		}
+
+		return plans, proposals, err
+	}
+
+	if currentPlan.Plan != nil {
+		if upgrade, ok := toUpgrade(chainName, *currentPlan.Plan); ok {
+			plans[upgrade.Name] = upgrade
+		}
+	}
+
+	return plans, proposals, nil
+}
+
+// getPassedUpgradeProposals returns the software upgrades of the latest
+// passed proposals of the chain, see passedUpgrades
+func getPassedUpgradeProposals(ctx types.Context, chainName, endpoint string) ([]database.Upgrade, error) {
+	version := "v1beta1"
+	isV1 := utils.UseGovV1(ctx, chainName, endpoint)
+	if isV1 {
+		version = "v1"
+	}
+
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/gov/" + version + "/proposals",
+		Method:   http.MethodGet,
+		QueryParams: types.QueryParams{
+			"proposal_status":    "3",
+			"pagination.reverse": "true",
+			"pagination.limit":   passedProposalsLimit,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var passed []passedProposal
+	if isV1 {
+		var proposals types.Proposals
+		if err := json.Unmarshal(resp.Body, &proposals); err != nil {
+			return nil, err
+		}
+
+		for _, proposal := range proposals.Proposals {
+			p := passedProposal{ID: proposal.ID}
+			for _, message := range proposal.Messages {
+				p.Messages = append(p.Messages, message.Raw)
+			}
+			passed = append(passed, p)
+		}
+	} else {
+		var proposals types.LegacyProposals
+		if err := json.Unmarshal(resp.Body, &proposals); err != nil {
+			return nil, err
+		}
+
+		for _, proposal := range proposals.Proposals {
+			passed = append(passed, passedProposal{ID: proposal.ProposalID, Messages: []json.RawMessage{proposal.Content.Raw}})
+		}
+	}
+
+	return passedUpgrades(chainName, passed), nil
+}
+
+// passedProposal holds the messages, or the legacy content, of a passed
+// proposal
+type passedProposal struct {
+	ID       string
+	Messages []json.RawMessage
+}
+
+// passedUpgrades returns the software upgrades of the passed proposals, which
+// are ordered the latest first. A chain has a single plan, so only the upgrade
+// of the latest upgrade or cancel proposal is upcoming. Older upgrades were
+// cancelled or replaced and are returned as cancelled.
+func passedUpgrades(chainName string, proposals []passedProposal) []database.Upgrade {
+	var upgrades []database.Upgrade
+	superseded := false
+	for _, proposal := range proposals {
+		// the last message of a proposal is applied last
+		for i := len(proposal.Messages) - 1; i >= 0; i-- {
+			plan, cancel, ok := upgradePlan(proposal.Messages[i])
+			if !ok {
+				continue
+			}
+
+			if cancel {
+				superseded = true
+				continue
+			}
+
+			if upgrade, ok := toUpgrade(chainName, plan); ok {
+				upgrade.ProposalID = proposal.ID
+				upgrade.Status = database.UpgradeUpcoming
+				if superseded {
+					upgrade.Status = database.UpgradeCancelled
+				}
+				upgrades = append(upgrades, upgrade)
+			}
+			superseded = true
+		}
+	}
+
+	return upgrades
+}
+
+// upgradePlan returns the plan of a software upgrade message or legacy
+// content, including legacy content executed by gov v1. cancel is true for
+// messages cancelling the planned upgrade.
+func upgradePlan(raw json.RawMessage) (plan types.Plan, cancel bool, ok bool) {
+	var msg struct {
+		Type    string          `json:"@type"`
+		Plan    types.Plan      `json:"plan"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return types.Plan{}, false, false
+	}
+
+	switch msg.Type {
+	case "/cosmos.gov.v1.MsgExecLegacyContent":
+		return upgradePlan(msg.Content)
+	case "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade", "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal":
+		return msg.Plan, false, msg.Plan.Name != ""
+	case "/cosmos.upgrade.v1beta1.MsgCancelUpgrade", "/cosmos.upgrade.v1beta1.CancelSoftwareUpgradeProposal":
+		return types.Plan{}, true, true
+	}
+
+	return types.Plan{}, false, false
+}
+
+// toUpgrade converts the plan, plans without a height are left out
+func toUpgrade(chainName string, plan types.Plan) (database.Upgrade, bool) {
+	height, err := strconv.ParseInt(plan.Height, 10, 64)
+	if err != nil || height <= 0 || plan.Name == "" {
+		return database.Upgrade{}, false
+	}
+
+	return database.Upgrade{
+		ChainName: chainName,
+		Name:      plan.Name,
+		Height:    height,
+		Info:      plan.Info,
+	}, true
+}
+
+func getBlockHeader(endpoint, height string) (blockHeader, error) {
+	var block types.BlockResponse
+	if err := getJSON(endpoint+"/cosmos/base/tendermint/v1beta1/blocks/"+height, &block); err != nil {
+		return blockHeader{}, err
+	}
+
+	h, err := strconv.ParseInt(block.Block.Header.Height, 10, 64)
+	if err != nil {
+		return blockHeader{}, fmt.Errorf("invalid block height %q: %v", block.Block.Header.Height, err)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, block.Block.Header.Time)
+	if err != nil {
+		return blockHeader{}, fmt.Errorf("invalid block time %q: %v", block.Block.Header.Time, err)
+	}
+
+	return blockHeader{Height: h, Time: t}, nil
+}
+
+// estimateBlockTime averages the block time over the recent blocks
+func estimateBlockTime(endpoint string, latest blockHeader) time.Duration {
+	window := int64(blockTimeWindow)
+	if latest.Height <= window {
+		window = latest.Height - 1
+	}
+	if window <= 0 {
+		return defaultBlockTime
+	}
+
+	past, err := getBlockHeader(endpoint, strconv.FormatInt(latest.Height-window, 10))
+	if err != nil {
+		log.Printf("failed to get block %d, using the default block time: %v", latest.Height-window, err)
+		return defaultBlockTime
+	}
+
+	blockTime := latest.Time.Sub(past.Time) / time.Duration(window)
+	if blockTime <= 0 {
+		return defaultBlockTime
+	}
+
+	return blockTime
+}
+
+func estimateHeightTime(latest blockHeader, blockTime time.Duration, height int64) time.Time {
+	return latest.Time.Add(time.Duration(height-latest.Height) * blockTime)
+}
+
+// sendUpgradeReminder sends the countdown reminder of the upgrade once for
+// each threshold the estimated time left drops below
+func sendUpgradeReminder(ctx types.Context, upgrade database.Upgrade) {
+	timeLeft := time.Until(time.Unix(upgrade.EstimatedTime, 0))
+	threshold, ok := reminderThreshold(ctx.Config().Upgrades.Thresholds, timeLeft)
+	if !ok {
+		return
+	}
+
+	sent, err := ctx.Database().HasUpgradeReminder(upgrade.ChainName, upgrade.Name, threshold)
+	if err != nil {
+		log.Printf("failed to get reminders of %s upgrade %s: %v", upgrade.ChainName, upgrade.Name, err)
+		return
+	}
+	if sent {
+		return
+	}
+
+	if err := ctx.Database().AddUpgradeReminder(upgrade.ChainName, upgrade.Name, threshold); err != nil {
+		log.Printf("failed to store reminder of %s upgrade %s: %v", upgrade.ChainName, upgrade.Name, err)
+	}
+
+	text := fmt.Sprintf(":hourglass_flowing_sand: Software upgrade *%s* of %s at height %d, %s",
+		upgrade.Name, upgrade.ChainName, upgrade.Height, formatUpgradeETA(upgrade))
+	if timeLeft <= ctx.Config().Reminders.OnCallWindow {
+		if mention := ctx.Config().Reminders.OnCallMention(); mention != "" {
+			text = mention + " " + text
+		}
+	}
+
+	postUpgradeAlert(ctx, text)
+}
+
+// formatUpgradeETA formats the estimated time of the upgrade and the time
+// left to it
+func formatUpgradeETA(upgrade database.Upgrade) string {
+	eta := time.Unix(upgrade.EstimatedTime, 0).UTC()
+	text := fmt.Sprintf("estimated at %s (in %s)", eta.Format(time.RFC822), FormatTimeLeft(time.Until(eta)))
+	if upgrade.ProposalID != "" {
+		text += fmt.Sprintf(", proposal %s", upgrade.ProposalID)
+	}
+
+	return text
+}
+
+func postUpgradeAlert(ctx types.Context, text string) {
+	_, _, err := ctx.Slacker().APIClient().PostMessage(
+		ctx.Config().Slack.ChannelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil)),
+	)
+	if err != nil {
+		log.Printf("error on sending upgrade alert: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+)
+
+func upgradeMsg(name string, height int64) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"@type":"/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade","authority":"cosmos10d07y","plan":{"name":%q,"height":"%d"}}`, name, height))
+}
+
+func TestPassedUpgrades(t *testing.T) {
+	cancel := json.RawMessage(`{"@type":"/cosmos.upgrade.v1beta1.MsgCancelUpgrade","authority":"cosmos10d07y"}`)
+	legacyUpgrade := json.RawMessage(`{"@type":"/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal","title":"v14","plan":{"name":"v14","height":"500"}}`)
+	legacyCancel := json.RawMessage(`{"@type":"/cosmos.gov.v1.MsgExecLegacyContent","content":{"@type":"/cosmos.upgrade.v1beta1.CancelSoftwareUpgradeProposal","title":"cancel"}}`)
+	send := json.RawMessage(`{"@type":"/cosmos.bank.v1beta1.MsgSend"}`)
+
+	upgrade := func(name string, height int64, proposalID, status string) database.Upgrade {
+		return database.Upgrade{ChainName: "cosmoshub", Name: name, Height: height, ProposalID: proposalID, Status: status}
+	}
+
+	tests := []struct {
+		name      string
+		proposals []passedProposal
+		want      []database.Upgrade
+	}{
+		{
+			name:      "latest upgrade",
+			proposals: []passedProposal{{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 2000)}}},
+			want:      []database.Upgrade{upgrade("v16", 2000, "12", database.UpgradeUpcoming)},
+		},
+		{
+			name: "cancelled by a later proposal",
+			proposals: []passedProposal{
+				{ID: "13", Messages: []json.RawMessage{cancel}},
+				{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 2000)}},
+			},
+			want: []database.Upgrade{upgrade("v16", 2000, "12", database.UpgradeCancelled)},
+		},
+		{
+			name: "cancelled by legacy content",
+			proposals: []passedProposal{
+				{ID: "13", Messages: []json.RawMessage{legacyCancel}},
+				{ID: "12", Messages: []json.RawMessage{legacyUpgrade}},
+			},
+			want: []database.Upgrade{upgrade("v14", 500, "12", database.UpgradeCancelled)},
+		},
+		{
+			name: "replaced by a later upgrade",
+			proposals: []passedProposal{
+				{ID: "14", Messages: []json.RawMessage{upgradeMsg("v16", 2100)}},
+				{ID: "13", Messages: []json.RawMessage{cancel}},
+				{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 2000)}},
+				{ID: "10", Messages: []json.RawMessage{legacyUpgrade}},
+			},
+			want: []database.Upgrade{
+				upgrade("v16", 2100, "14", database.UpgradeUpcoming),
+				upgrade("v16", 2000, "12", database.UpgradeCancelled),
+				upgrade("v14", 500, "10", database.UpgradeCancelled),
+			},
+		},
+		{
+			name: "upgrade after a cancel",
+			proposals: []passedProposal{
+				{ID: "14", Messages: []json.RawMessage{upgradeMsg("v17", 3000)}},
+				{ID: "13", Messages: []json.RawMessage{cancel}},
+			},
+			want: []database.Upgrade{upgrade("v17", 3000, "14", database.UpgradeUpcoming)},
+		},
+		{
+			name: "cancel and upgrade in one proposal",
+			proposals: []passedProposal{
+				{ID: "15", Messages: []json.RawMessage{cancel, send, upgradeMsg("v17", 3000)}},
+				{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 2000)}},
+			},
+			want: []database.Upgrade{
+				upgrade("v17", 3000, "15", database.UpgradeUpcoming),
+				upgrade("v16", 2000, "12", database.UpgradeCancelled),
+			},
+		},
+		{
+			name: "other proposals are ignored",
+			proposals: []passedProposal{
+				{ID: "16", Messages: []json.RawMessage{send}},
+				{ID: "15"},
+				{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 2000), json.RawMessage(`not json`)}},
+			},
+			want: []database.Upgrade{upgrade("v16", 2000, "12", database.UpgradeUpcoming)},
+		},
+		{
+			name: "plan without height",
+			proposals: []passedProposal{
+				{ID: "12", Messages: []json.RawMessage{upgradeMsg("v16", 0)}},
+			},
+		},
+		{name: "no proposals"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, passedUpgrades("cosmoshub", tt.proposals))
+		})
+	}
+}
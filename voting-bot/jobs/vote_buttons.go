@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// VoteButtonActionPrefix starts the action ids of the vote buttons of
+// proposal alerts
+const VoteButtonActionPrefix = "vote_button_"
+
+// voteButtonOptions are the options offered by the vote buttons, with their
+// labels
+var voteButtonOptions = []struct {
+	option, label string
+	style         slack.Style
+}{
+	{"yes", "Yes", slack.StylePrimary},
+	{"no", "No", slack.StyleDefault},
+	{"abstain", "Abstain", slack.StyleDefault},
+	{"no_with_veto", "No With Veto", slack.StyleDanger},
+}
+
+// voteButtons returns the buttons voting on the proposal for the validator.
+// The value of a button holds the validator, the proposal and the option.
+func voteButtons(valAddr, proposalID string) *slack.ActionBlock {
+	var elements []slack.BlockElement
+	for _, o := range voteButtonOptions {
+		button := slack.NewButtonBlockElement(
+			VoteButtonActionPrefix+o.option,
+			strings.Join([]string{valAddr, proposalID, o.option}, " "),
+			slack.NewTextBlockObject("plain_text", o.label, false, false),
+		)
+		if o.style != "" {
+			button = button.WithStyle(o.style)
+		}
+		if o.option == "no_with_veto" {
+			button = button.WithConfirm(slack.NewConfirmationBlockObject(
+				slack.NewTextBlockObject("plain_text", "Vote No With Veto?", false, false),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("Votes no with veto on proposal %s for %s", proposalID, valAddr), false, false),
+				slack.NewTextBlockObject("plain_text", "Vote", false, false),
+				slack.NewTextBlockObject("plain_text", "Cancel", false, false),
+			))
+		}
+		elements = append(elements, button)
+	}
+
+	return slack.NewActionBlock(VoteButtonsBlockID(valAddr, proposalID), elements...)
+}
+
+// VoteButtonsBlockID identifies the vote buttons of the validator and the
+// proposal within an alert
+func VoteButtonsBlockID(valAddr, proposalID string) string {
+	return fmt.Sprintf("vote_buttons_%s_%s", valAddr, proposalID)
+}
+
+// ParseVoteButtonValue returns the validator, the proposal and the option of
+// a vote button
+func ParseVoteButtonValue(value string) (valAddr, proposalID, option string, err error) {
+	fields := strings.Fields(value)
+	if len(fields) != 3 {
+		return "", "", "", fmt.Errorf("invalid vote button value %q", value)
+	}
+
+	return fields[0], fields[1], fields[2], nil
+}
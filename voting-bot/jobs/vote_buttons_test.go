@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVoteButtonValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		valAddr    string
+		proposalID string
+		option     string
+		wantErr    bool
+	}{
+		{name: "button value", value: "cosmosvaloper1abc 12 yes", valAddr: "cosmosvaloper1abc", proposalID: "12", option: "yes"},
+		{name: "no with veto", value: "osmovaloper1xyz 7 no_with_veto", valAddr: "osmovaloper1xyz", proposalID: "7", option: "no_with_veto"},
+		{name: "extra white space", value: " cosmosvaloper1abc  12\tabstain ", valAddr: "cosmosvaloper1abc", proposalID: "12", option: "abstain"},
+		{name: "missing option", value: "cosmosvaloper1abc 12", wantErr: true},
+		{name: "extra field", value: "cosmosvaloper1abc 12 yes now", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valAddr, proposalID, option, err := ParseVoteButtonValue(tt.value)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid vote button value")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.valAddr, valAddr)
+			assert.Equal(t, tt.proposalID, proposalID)
+			assert.Equal(t, tt.option, option)
+		})
+	}
+}
+
+func TestVoteButtons(t *testing.T) {
+	block := voteButtons("cosmosvaloper1abc", "12")
+	assert.Equal(t, "vote_buttons_cosmosvaloper1abc_12", block.BlockID)
+	require.Len(t, block.Elements.ElementSet, len(voteButtonOptions))
+
+	for i, element := range block.Elements.ElementSet {
+		button, ok := element.(*slack.ButtonBlockElement)
+		require.True(t, ok)
+
+		// every button value parses back to the vote it casts
+		valAddr, proposalID, option, err := ParseVoteButtonValue(button.Value)
+		assert.NoError(t, err)
+		assert.Equal(t, "cosmosvaloper1abc", valAddr)
+		assert.Equal(t, "12", proposalID)
+		assert.Equal(t, voteButtonOptions[i].option, option)
+		assert.Equal(t, VoteButtonActionPrefix+option, button.ActionID)
+		assert.Equal(t, option == "no_with_veto", button.Confirm != nil)
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// Expires the vote requests which were not approved in time and alerts on them
+func ExpireVoteRequests(ctx types.Context) error {
+	db := ctx.Database()
+	expired, err := db.ExpireVoteRequests()
+	if err != nil {
+		return fmt.Errorf("failed to expire vote requests: %v", err)
+	}
+
+	var errs jobErrors
+
+	for _, req := range expired {
+		if err := db.AddVoteAudit(req.ID, "", database.VoteRequestExpired, "approval timeout passed"); err != nil {
+			errs.addf("failed to store audit of vote request %d: %v", req.ID, err)
+		}
+		voting.AddAuditEntry(ctx, database.AuditEntry{
+			Command:   "expire-vote-request",
+			ChainName: targetChainName(ctx, req.Target),
+			Params:    fmt.Sprintf("#%d %s", req.ID, voting.DescribeVoteRequest(req)),
+			Result:    database.AuditResultOK,
+		})
+
+		msg := fmt.Sprintf("Vote request #%d (%s %s on proposal %s for %s) expired with %d approvals",
+			req.ID, req.VoteType, req.VoteOption, req.ProposalID, req.Target, req.Approvals)
+		if err := sendPlainAlert(ctx, msg); err != nil {
+			errs.addf("failed to send vote request expiry alert: %v", err)
+		}
+	}
+
+	return errs.err()
+}
@@ -0,0 +1,119 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/vitwit/authz-apps/voting-bot/config"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/voting"
+)
+
+// applyVotingRule matches the voting rules against a proposal the validator
+// has not voted on. The vote of a matched auto vote rule is cast once, other
+// rules are returned to suggest their vote in the alert. It returns the
+// matched rule and true if the vote was cast.
+func applyVotingRule(ctx types.Context, chainName, valAddr string, proposal ActiveProposalResult) (*config.VotingRuleConfig, bool) {
+	rule, ok := matchVotingRule(ctx, chainName, proposal)
+	if !ok {
+		return nil, false
+	}
+
+	db := ctx.Database()
+	last, found, err := db.GetRuleDecision(chainName, valAddr, proposal.ProposalID)
+	if err != nil {
+		log.Printf("failed to get rule decision of %s on proposal %s: %v", valAddr, proposal.ProposalID, err)
+		return &rule, false
+	}
+
+	decision := database.RuleDecision{
+		ChainName:        chainName,
+		ValidatorAddress: valAddr,
+		ProposalID:       proposal.ProposalID,
+		RuleName:         rule.Name,
+		VoteOption:       rule.VoteOption,
+		Rationale:        rule.Rationale,
+		Action:           database.RuleActionSuggested,
+	}
+
+	// a vote which was already tried is only suggested from then on
+	if rule.AutoVote && (!found || last.Action == database.RuleActionSuggested) {
+		responseWriter := voting.NewAuditWriter(voting.ProposalThreadWriter(ctx, chainName, proposal.ProposalID))
+		responseWriter.Reply(fmt.Sprintf("Voting rule *%s* matched %s proposal %s (%s), voting %s for %s: %s",
+			rule.Name, chainName, proposal.ProposalID, proposal.Title, rule.VoteOption, valAddr, rule.Rationale))
+
+		err := voting.ExecVoteRequest(ctx, database.VoteRequest{
+			Target:      valAddr,
+			ProposalID:  proposal.ProposalID,
+			VoteType:    database.VoteTypeSingle,
+			VoteOption:  rule.VoteOption,
+			GasPrices:   rule.GasPrices,
+			Memo:        rule.Rationale,
+			RequestedBy: "rule " + rule.Name,
+		}, false, responseWriter)
+
+		decision.Action = database.RuleActionVoted
+		if err != nil {
+			decision.Action = database.RuleActionVoteFailed
+			decision.Details = err.Error()
+		}
+
+		if err := db.AddRuleDecision(decision); err != nil {
+			log.Printf("failed to store rule decision: %v", err)
+		}
+
+		voting.AddAuditEntry(ctx, responseWriter.Entry(database.AuditEntry{
+			Command:   "voting-rule",
+			ChainName: chainName,
+			Params:    fmt.Sprintf("rule %s: %s on proposal %s for %s", rule.Name, rule.VoteOption, proposal.ProposalID, valAddr),
+		}))
+
+		return &rule, decision.Action == database.RuleActionVoted
+	}
+
+	if !found {
+		if err := db.AddRuleDecision(decision); err != nil {
+			log.Printf("failed to store rule decision: %v", err)
+		}
+	}
+
+	return &rule, false
+}
+
+// applyNewProposalRule applies the voting rules to a proposal found by the
+// new proposals poll, which does not query the votes of the validators. The
+// vote of the validator is queried for auto vote rules only, so that a
+// validator which voted is not voted for. If the query fails the rule is
+// only suggested, the alert job applies it later.
+func applyNewProposalRule(ctx types.Context, isV1 bool, endpoint, chainName, valAddr string,
+	proposal ActiveProposalResult,
+) (*config.VotingRuleConfig, bool) {
+	rule, ok := matchVotingRule(ctx, chainName, proposal)
+	if !ok {
+		return nil, false
+	}
+
+	if rule.AutoVote {
+		vote, err := voting.GetValidatorVoteOption(ctx, isV1, chainName, endpoint, proposal.ProposalID, valAddr)
+		if err != nil {
+			log.Printf("failed to get vote of %s on %s proposal %s: %v", valAddr, chainName, proposal.ProposalID, err)
+			return &rule, false
+		}
+		if vote != "" {
+			return &rule, false
+		}
+	}
+
+	return applyVotingRule(ctx, chainName, valAddr, proposal)
+}
+
+// matchVotingRule returns the first voting rule matching the proposal
+func matchVotingRule(ctx types.Context, chainName string, proposal ActiveProposalResult) (config.VotingRuleConfig, bool) {
+	return voting.MatchVotingRule(ctx.Config().VotingRules, voting.RuleProposal{
+		ChainName:    chainName,
+		Title:        proposal.Title,
+		Proposer:     proposal.Proposer,
+		MessageTypes: proposal.MessageTypes,
+	})
+}
@@ -1,7 +1,6 @@
 package jobs
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +10,7 @@ import (
 	"github.com/slack-go/slack"
 	lensclient "github.com/strangelove-ventures/lens/client"
 	registry "github.com/strangelove-ventures/lens/client/chain_registry"
+	"github.com/vitwit/authz-apps/voting-bot/database"
 	"github.com/vitwit/authz-apps/voting-bot/endpoints"
 	"github.com/vitwit/authz-apps/voting-bot/types"
 	"github.com/vitwit/authz-apps/voting-bot/utils"
@@ -39,6 +39,7 @@ func Withdraw(ctx types.Context) error {
 		return err
 	}
 
+	var errs jobErrors
 	for _, key := range keys {
 		if key.Type != "rewards" {
 			continue // TODO: fetch only rewards records
@@ -46,8 +47,8 @@ func Withdraw(ctx types.Context) error {
 
 		chainInfo, chainClient, err := createChainClient(ctx, key.ChainName, key.KeyName)
 		if err != nil {
-			log.Printf("Error in creating chain client for %s chain", key.ChainName)
-			return err
+			errs.addf("Error in creating chain client for %s chain: %v", key.ChainName, err)
+			continue
 		}
 
 		for _, val := range validators {
@@ -61,9 +62,9 @@ func Withdraw(ctx types.Context) error {
 				// Set the time to the beginning of the month
 				startOfMonth := time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, currentTime.Location())
 				currentDate := startOfMonth.Format("2006-01-02")
-				exist, err := ctx.Database().IsIncomeRecordExist(chainInfo.ChainID, currentDate)
+				exist, err := ctx.Database().IsIncomeRecordExist(chainInfo.ChainID, val.Address, currentDate)
 				if err != nil {
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: SQL error for %s chain: %v", key.ChainName, err))
+					errs.alertf(ctx, "withdraw rewards and commission job: SQL error for %s chain: %v", key.ChainName, err)
 					continue
 				}
 				if exist {
@@ -74,20 +75,20 @@ func Withdraw(ctx types.Context) error {
 				if err != nil {
 					log.Printf("Error in getting valid LCD endpoints for %s chain", key.ChainName)
 
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in getting valid LCD endpoints for %s chain", key.ChainName))
+					errs.alertf(ctx, "withdraw rewards and commission job: Error in getting valid LCD endpoints for %s chain", key.ChainName)
 					continue
 				}
 
 				var msgs []*cdctypes.Any
-				granter, err := ConvertValAddrToAccAddr(ctx, val.Address, key.ChainName)
+				granter, err := utils.ConvertValAddrToAccAddr(ctx, val.Address, key.ChainName)
 				if err != nil {
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: failed to decode validator address for %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: failed to decode validator address for %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
 				hasAuthz, err := utils.HasAuthzGrant(validEndpoint, granter, key.GranteeAddress, WITHDRAW_REWARDS_TYPEURL)
 				if err != nil {
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: failed to get authz status for %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: failed to get authz status for %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
@@ -95,7 +96,7 @@ func Withdraw(ctx types.Context) error {
 					msg, err := withdrawRewardsMsg(granter, val.Address)
 					if err != nil {
 						log.Printf("Error in creating withdraw rewards message for %s", val.Address)
-						sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in creating withdraw rewards message for %s chain: %s", key.ChainName, err.Error()))
+						errs.alertf(ctx, "withdraw rewards and commission job: Error in creating withdraw rewards message for %s chain: %s", key.ChainName, err.Error())
 						continue
 					}
 
@@ -104,14 +105,14 @@ func Withdraw(ctx types.Context) error {
 
 				hasAuthz, err = utils.HasAuthzGrant(validEndpoint, granter, key.GranteeAddress, WITHDRAW_COMMISSION)
 				if err != nil {
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: failed to get authz status for %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: failed to get authz status for %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
 				if hasAuthz {
 					msg, err := withdrawCommissionMsg(val.Address)
 					if err != nil {
-						sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in creating withdraw commission message for %s chain: %s", key.ChainName, err.Error()))
+						errs.alertf(ctx, "withdraw rewards and commission job: Error in creating withdraw commission message for %s chain: %s", key.ChainName, err.Error())
 						continue
 					}
 
@@ -119,16 +120,28 @@ func Withdraw(ctx types.Context) error {
 				}
 
 				if len(msgs) == 0 {
-					return nil
+					continue
 				}
 
-				res, err := executeMsgs(chainClient, msgs, key.GranteeAddress)
+				feeGranter := voting.FeeGranter(validEndpoint, granter, key.GranteeAddress)
+				res, sim, err := executeMsgs(chainClient, msgs, key.GranteeAddress, feeGranter)
+				entry := database.AuditEntry{
+					Command:   "withdraw-rewards",
+					ChainName: val.ChainName,
+					Params:    fmt.Sprintf("%d msgs for %s", len(msgs), val.Address),
+					Result:    database.AuditResultOK,
+				}
 				if err != nil {
+					entry.Result = fmt.Sprintf("%s: %v", database.AuditResultError, err)
+					voting.AddAuditEntry(ctx, entry)
 					log.Printf("Error in creating withdraw commission message for %s", val.Address)
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in executing transaction for %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: Error in executing transaction for %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
+				entry.TxHash = res.TxHash
+				voting.AddAuditEntry(ctx, entry)
+
 				mintscanName := val.ChainName
 				if newName, ok := utils.RegisrtyNameToMintscanName[val.ChainName]; ok {
 					mintscanName = newName
@@ -137,30 +150,30 @@ func Withdraw(ctx types.Context) error {
 				rewards, err := getRewardAmount(res, "withdraw_rewards")
 				if err != nil {
 					log.Printf("Error in getting rewards from tx resp for chain %s. txhash: %s", key.ChainName, res.TxHash)
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in getting rewards from tx resp for chain %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: Error in getting rewards from tx resp for chain %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
 				commission, err := getRewardAmount(res, "withdraw_commission")
 				if err != nil {
 					log.Printf("Error in getting rewards from tx resp for chain %s. txhash: %s", key.ChainName, res.TxHash)
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in getting rewards from tx resp for chain %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: Error in getting rewards from tx resp for chain %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
 				url := fmt.Sprintf("https://mintscan.io/%s/txs/%s", mintscanName, res.TxHash)
-				SendMsgExecAlert(ctx, val.Address, url)
+				SendMsgExecAlert(ctx, val.Address, url, sim.String())
 
 				denom, err := voting.GetChainDenom(chainInfo)
 				if err != nil {
 					log.Printf("Error in getting denom for chain %s", val.ChainName)
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Error in getting denom for chain %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: Error in getting denom for chain %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
 				if err := ctx.Database().AddRewards(chainInfo.ChainID, denom, val.Address, rewards.String(), commission.String()); err != nil {
 					log.Printf("Failed to store reward and commission for %s on %s", val.Address, val.ChainName)
-					sendPlainAlert(ctx, fmt.Sprintf("withdraw rewards and commission job: Failed to store reward and commission for chain %s chain: %s", key.ChainName, err.Error()))
+					errs.alertf(ctx, "withdraw rewards and commission job: Failed to store reward and commission for chain %s chain: %s", key.ChainName, err.Error())
 					continue
 				}
 
@@ -168,7 +181,8 @@ func Withdraw(ctx types.Context) error {
 			}
 		}
 	}
-	return nil
+
+	return errs.err()
 }
 
 func createChainClient(ctx types.Context, chainName, keyName string) (registry.ChainInfo, lensclient.ChainClient, error) {
@@ -190,7 +204,7 @@ func createChainClient(ctx types.Context, chainName, keyName string) (registry.C
 	}
 
 	gasPrices := "0.005" + denom
-	chainConfig := utils.GetChainConfig(keyName, chainInfo, gasPrices, rpc, 2.1)
+	chainConfig := utils.GetChainConfig(keyName, chainInfo, gasPrices, rpc, ctx.Config().Gas.WithdrawAdjustment)
 
 	curDir, err := os.Getwd()
 	if err != nil {
@@ -205,22 +219,31 @@ func createChainClient(ctx types.Context, chainName, keyName string) (registry.C
 	return chainInfo, *chainClient, nil
 }
 
-func executeMsgs(chainClient lensclient.ChainClient, msgs []*cdctypes.Any, keyAddr string) (*sdk.TxResponse, error) {
+// executeMsgs simulates the msgs wrapped in an authz MsgExec and broadcasts
+// them when the simulation succeeds. The fees are paid by feeGranter if it
+// is set.
+func executeMsgs(chainClient lensclient.ChainClient, msgs []*cdctypes.Any, keyAddr, feeGranter string) (*sdk.TxResponse, voting.Simulation, error) {
 	req := &authz.MsgExec{
 		Grantee: keyAddr,
 		Msgs:    msgs,
 	}
 
+	sim, err := voting.Simulate(&chainClient, req)
+	if err != nil {
+		return nil, voting.Simulation{}, err
+	}
+	log.Printf("Simulated withdraw transaction of %s: %s", keyAddr, sim)
+
 	// Send msg and get response
-	res, err := chainClient.SendMsg(context.Background(), req, "")
+	res, err := voting.SendMsgs(&chainClient, []sdk.Msg{req}, "", feeGranter)
 	if err != nil {
 		if res != nil {
-			return nil, fmt.Errorf("failed to vote on proposal: code(%d) msg(%s)", res.Code, res.Logs)
+			return nil, sim, fmt.Errorf("failed to vote on proposal: code(%d) msg(%s)", res.Code, res.Logs)
 		}
-		return nil, fmt.Errorf("failed to vote.Err: %v", err)
+		return nil, sim, fmt.Errorf("failed to vote.Err: %v", err)
 	}
 
-	return res, nil
+	return res, sim, nil
 }
 
 // Generate withdraw rewards message
@@ -257,12 +280,12 @@ func withdrawCommissionMsg(valAddr string) (*cdctypes.Any, error) {
 }
 
 // SendMsgExecAlert which sends alerts on withdraw rewards and commission txs
-func SendMsgExecAlert(ctx types.Context, valAddr, URL string) error {
+func SendMsgExecAlert(ctx types.Context, valAddr, URL, simulation string) error {
 	api := ctx.Slacker().APIClient()
 
 	attachment := []slack.Block{
 		slack.NewHeaderBlock(
-			slack.NewTextBlockObject("plain_text", fmt.Sprintf("Withdraw rewards and commission executed for %s\nTransaction broadcasted: %s", valAddr, URL), false, false),
+			slack.NewTextBlockObject("plain_text", fmt.Sprintf("Withdraw rewards and commission executed for %s\nTransaction broadcasted: %s\nSimulation: %s", valAddr, URL, simulation), false, false),
 		),
 	}
 
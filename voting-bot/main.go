@@ -34,6 +34,11 @@ func main() {
 		}
 	}()
 
+	cfg, err := config.ReadConfigFromFile()
+	if err != nil {
+		logger.Error().Err(err)
+	}
+
 	// Initialize the router
 	router := mux.NewRouter()
 
@@ -49,6 +54,14 @@ func main() {
 	router.HandleFunc("/rewards", handler.GetRewardsHandler(db)).Methods("OPTIONS", "GET")
 	router.HandleFunc("/votes/{chainName}", handler.RetrieveProposalsHandler(db)).Methods("OPTIONS", "GET")
 	router.HandleFunc("/votes", handler.RetrieveProposalsForAllNetworksHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/stats/{chainName}", handler.GetVoteStatsHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/stats", handler.GetVoteStatsHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/outcomes/{chainName}", handler.GetProposalOutcomesHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/outcomes", handler.GetProposalOutcomesHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/upgrades/{chainName}", handler.GetUpgradesHandler(db)).Methods("OPTIONS", "GET")
+	router.HandleFunc("/upgrades", handler.GetUpgradesHandler(db)).Methods("OPTIONS", "GET")
+	// the audit log requires the audit token and is not shared with other origins
+	router.HandleFunc("/audit", handler.GetAuditLogHandler(db, cfg.API.AuditToken)).Methods("GET")
 
 	// CORS middleware
 	http.Handle("/", corsMiddleware(router))
@@ -59,18 +72,13 @@ func main() {
 		log.Error().Err(http.ListenAndServe(":8080", router))
 	}()
 
-	cfg, err := config.ReadConfigFromFile()
-	if err != nil {
-		logger.Error().Err(err)
-	}
-
 	bot := slacker.NewClient(cfg.Slack.BotToken, cfg.Slack.AppToken)
 	logger.Info().Msg("bot connected")
 	ctx := types.NewContext(logger, db, cfg, bot)
 
 	fmt.Println(bot.BotCommands())
 
-	cron := jobs.NewCron(ctx)
+	cron := jobs.NewCron(ctx, client.AppHomeViewer(ctx))
 	cron.Start()
 
 	client.InitializeBotcommands(ctx)
@@ -3,7 +3,8 @@ package types
 import "time"
 
 type Grants struct {
-	Grants []Grant `json:"grants"`
+	Grants     []Grant    `json:"grants"`
+	Pagination Pagination `json:"pagination"`
 }
 
 type Grant struct {
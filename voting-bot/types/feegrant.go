@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+type FeeAllowanceResponse struct {
+	Allowance struct {
+		Granter   string       `json:"granter"`
+		Grantee   string       `json:"grantee"`
+		Allowance FeeAllowance `json:"allowance"`
+	} `json:"allowance"`
+}
+
+// FeeAllowance holds the fields of the basic, periodic and allowed msg fee
+// allowances. The periodic and allowed msg allowances wrap another allowance.
+type FeeAllowance struct {
+	Type string `json:"@type"`
+
+	// BasicAllowance
+	SpendLimit []Coin     `json:"spend_limit"`
+	Expiration *time.Time `json:"expiration"`
+
+	// PeriodicAllowance
+	Basic            *FeeAllowance `json:"basic"`
+	Period           string        `json:"period"`
+	PeriodSpendLimit []Coin        `json:"period_spend_limit"`
+	PeriodCanSpend   []Coin        `json:"period_can_spend"`
+	PeriodReset      *time.Time    `json:"period_reset"`
+
+	// AllowedMsgAllowance
+	Allowance       *FeeAllowance `json:"allowance"`
+	AllowedMessages []string      `json:"allowed_messages"`
+}
+
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
@@ -1,22 +1,16 @@
 package types
 
+import "encoding/json"
+
 type LegacyProposals struct {
-	Proposals []LegacyProposal `json:"proposals"`
+	Proposals  []LegacyProposal `json:"proposals"`
+	Pagination Pagination       `json:"pagination"`
 }
 
 type LegacyProposal struct {
-	ProposalID string `json:"proposal_id"`
-	Content    struct {
-		Type        string `json:"@type"`
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Changes     []struct {
-			Subspace string `json:"subspace"`
-			Key      string `json:"key"`
-			Value    string `json:"value"`
-		} `json:"changes"`
-	} `json:"content,omitempty"`
-	Status           string `json:"status"`
+	ProposalID       string        `json:"proposal_id"`
+	Content          LegacyContent `json:"content,omitempty"`
+	Status           string        `json:"status"`
 	FinalTallyResult struct {
 		Yes        string `json:"yes"`
 		Abstain    string `json:"abstain"`
@@ -33,22 +27,77 @@ type LegacyProposal struct {
 	VotingEndTime   string `json:"voting_end_time"`
 }
 
+type LegacyContent struct {
+	Type        string `json:"@type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Changes     []struct {
+		Subspace string `json:"subspace"`
+		Key      string `json:"key"`
+		Value    string `json:"value"`
+	} `json:"changes"`
+	// Raw is the JSON of the content, used to decode the fields of the
+	// content type
+	Raw json.RawMessage `json:"-"`
+}
+
+func (c *LegacyContent) UnmarshalJSON(data []byte) error {
+	type content LegacyContent
+	if err := json.Unmarshal(data, (*content)(c)); err != nil {
+		return err
+	}
+
+	c.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+type LegacyProposalResponse struct {
+	Proposal LegacyProposal `json:"proposal"`
+}
+
 type Proposals struct {
-	Proposals []Proposal `json:"proposals"`
+	Proposals  []Proposal `json:"proposals"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type ProposalResponse struct {
+	Proposal Proposal `json:"proposal"`
 }
 
 type Proposal struct {
-	ID            string      `json:"id"`
-	Messages      []Message   `json:"messages"`
-	Status        string      `json:"status"`
-	Metadata      interface{} `json:"metadata"`
-	VotingEndTime string      `json:"voting_end_time"`
+	ID               string      `json:"id"`
+	Messages         []Message   `json:"messages"`
+	Status           string      `json:"status"`
+	FinalTallyResult TallyResult `json:"final_tally_result"`
+	Metadata         interface{} `json:"metadata"`
+	SubmitTime       string      `json:"submit_time"`
+	DepositEndTime   string      `json:"deposit_end_time"`
+	TotalDeposit     []Coin      `json:"total_deposit"`
+	VotingStartTime  string      `json:"voting_start_time"`
+	VotingEndTime    string      `json:"voting_end_time"`
+	Proposer         string      `json:"proposer"`
+	// Title and summary are only set from SDK v0.47
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
 }
 
 type Message struct {
 	Type    string  `json:"@type"`
 	Title   string  `json:"title"`
 	Content Content `json:"content"`
+	// Raw is the JSON of the message, used to decode the fields of the
+	// message type
+	Raw json.RawMessage `json:"-"`
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type message Message
+	if err := json.Unmarshal(data, (*message)(m)); err != nil {
+		return err
+	}
+
+	m.Raw = append(json.RawMessage(nil), data...)
+	return nil
 }
 
 type Content struct {
@@ -66,6 +115,38 @@ type Metadata struct {
 	VoteOptionContext string   `json:"vote_option_context"`
 }
 
+type TallyResponse struct {
+	Tally TallyResult `json:"tally"`
+}
+
+// TallyResult holds the tally of gov v1, which ends the options with _count,
+// and of gov v1beta1
+type TallyResult struct {
+	Yes             string `json:"yes"`
+	Abstain         string `json:"abstain"`
+	No              string `json:"no"`
+	NoWithVeto      string `json:"no_with_veto"`
+	YesCount        string `json:"yes_count"`
+	AbstainCount    string `json:"abstain_count"`
+	NoCount         string `json:"no_count"`
+	NoWithVetoCount string `json:"no_with_veto_count"`
+}
+
+type TallyParamsResponse struct {
+	TallyParams struct {
+		Quorum        string `json:"quorum"`
+		Threshold     string `json:"threshold"`
+		VetoThreshold string `json:"veto_threshold"`
+	} `json:"tally_params"`
+}
+
+type StakingPoolResponse struct {
+	Pool struct {
+		NotBondedTokens string `json:"not_bonded_tokens"`
+		BondedTokens    string `json:"bonded_tokens"`
+	} `json:"pool"`
+}
+
 type VoteResponse struct {
 	Vote Vote `json:"vote"`
 }
@@ -16,6 +16,12 @@ type (
 		Method      string
 	}
 
+	// Pagination of list queries, next_key is empty on the last page
+	Pagination struct {
+		NextKey string `json:"next_key"`
+		Total   string `json:"total"`
+	}
+
 	// PingResp struct
 	PingResp struct {
 		StatusCode int
@@ -0,0 +1,24 @@
+package types
+
+// CurrentPlanResponse holds the upgrade plan of a chain, plan is nil if no
+// upgrade is planned
+type CurrentPlanResponse struct {
+	Plan *Plan `json:"plan"`
+}
+
+type Plan struct {
+	Name   string `json:"name"`
+	Height string `json:"height"`
+	Info   string `json:"info"`
+}
+
+// BlockResponse holds the header of a block returned by the tendermint
+// service
+type BlockResponse struct {
+	Block struct {
+		Header struct {
+			Height string `json:"height"`
+			Time   string `json:"time"`
+		} `json:"header"`
+	} `json:"block"`
+}
@@ -9,26 +9,29 @@ import (
 )
 
 // HasAuthzGrant returns true if authz permission is exist for the provided
-// parameters.
+// parameters. All pages of the grants are checked.
 func HasAuthzGrant(endpoint, granter, grantee, typeURL string) (bool, error) {
-	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+	found := false
+	err := endpoints.HitPaginatedTarget(types.HTTPOptions{
 		Endpoint:    endpoint + "/cosmos/authz/v1beta1/grants",
 		Method:      http.MethodGet,
 		QueryParams: types.QueryParams{"granter": granter, "grantee": grantee, "msg_type_url": typeURL},
+	}, func(resp *types.PingResp) (string, error) {
+		var authzResp types.Grants
+		if err := json.Unmarshal(resp.Body, &authzResp); err != nil {
+			return "", err
+		}
+
+		if len(authzResp.Grants) > 0 {
+			found = true
+			return "", nil
+		}
+
+		return authzResp.Pagination.NextKey, nil
 	})
 	if err != nil {
 		return false, err
 	}
 
-	var authzResp types.Grants
-	if err := json.Unmarshal(resp.Body, &authzResp); err != nil {
-		return false, err
-	}
-
-	if len(authzResp.Grants) > 0 {
-		return true, nil
-	}
-
-	return false, nil
-
+	return found, nil
 }
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"context"
+	"encoding/hex"
 	"sync"
 
 	registry "github.com/strangelove-ventures/lens/client/chain_registry"
+	"github.com/vitwit/authz-apps/voting-bot/types"
 )
 
 var prefixMutex sync.Mutex
@@ -20,3 +23,29 @@ func SetBech32Prefixes(chainInfo registry.ChainInfo) func() {
 
 	return prefixMutex.Unlock
 }
+
+// ConvertValAddrToAccAddr returns the account address of the validator
+// operator address using the bech32 prefix of the chain.
+func ConvertValAddrToAccAddr(ctx types.Context, valAddr, chainName string) (string, error) {
+	chainInfo, err := ctx.ChainRegistry().GetChain(context.Background(), chainName)
+	if err != nil {
+		return "", err
+	}
+
+	done := SetBech32Prefixes(chainInfo)
+	addr, err := ValAddressFromBech32(valAddr)
+	if err != nil {
+		done()
+		return "", err
+	}
+
+	accAddr, err := AccAddressFromHexUnsafe(hex.EncodeToString(addr.Bytes()))
+	if err != nil {
+		done()
+		return "", err
+	}
+
+	accAddrString := accAddr.String()
+	done()
+	return accAddrString, nil
+}
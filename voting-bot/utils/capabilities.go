@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// capabilitiesRefreshInterval is how long the detected capabilities of a
+// chain are used before the chain is probed again
+const capabilitiesRefreshInterval = 24 * time.Hour
+
+type nodeInfo struct {
+	ApplicationVersion struct {
+		CosmosSDKVersion string `json:"cosmos_sdk_version"`
+	} `json:"application_version"`
+}
+
+// GetChainCapabilities returns the gov versions and the authz support of the
+// chain. They are probed on the REST endpoint and cached in the database
+// until the refresh interval passes. If the probe fails, a cached result is
+// used even when it is outdated.
+func GetChainCapabilities(ctx types.Context, chainName, endpoint string) (database.ChainCapabilities, error) {
+	cached, found, err := ctx.Database().GetChainCapabilities(chainName)
+	if err != nil {
+		log.Printf("failed to get capabilities of %s from the database: %v", chainName, err)
+	}
+
+	if found && time.Since(time.Unix(cached.CheckedAt, 0)) < capabilitiesRefreshInterval {
+		return cached, nil
+	}
+
+	caps, err := probeChainCapabilities(chainName, endpoint)
+	if err != nil {
+		if found {
+			log.Printf("failed to probe capabilities of %s, using the cached capabilities: %v", chainName, err)
+			return cached, nil
+		}
+		return database.ChainCapabilities{}, err
+	}
+
+	if err := ctx.Database().SetChainCapabilities(caps); err != nil {
+		log.Printf("failed to store capabilities of %s: %v", chainName, err)
+	}
+
+	return caps, nil
+}
+
+// UseGovV1 returns true if the chain supports gov v1. The v1beta1 API is
+// used if the capabilities of the chain can not be detected.
+func UseGovV1(ctx types.Context, chainName, endpoint string) bool {
+	caps, err := GetChainCapabilities(ctx, chainName, endpoint)
+	if err != nil {
+		log.Printf("failed to detect gov version of %s: %v", chainName, err)
+		return false
+	}
+
+	return caps.GovV1
+}
+
+// probeChainCapabilities queries the gov and authz APIs of the chain and
+// reads its SDK version from the node info
+func probeChainCapabilities(chainName, endpoint string) (database.ChainCapabilities, error) {
+	caps := database.ChainCapabilities{ChainName: chainName}
+
+	var err error
+	caps.GovV1, err = isRouteSupported(endpoint + "/cosmos/gov/v1/params/voting")
+	if err != nil {
+		return caps, err
+	}
+
+	caps.GovV1beta1, err = isRouteSupported(endpoint + "/cosmos/gov/v1beta1/params/voting")
+	if err != nil {
+		return caps, err
+	}
+
+	if !caps.GovV1 && !caps.GovV1beta1 {
+		return caps, fmt.Errorf("no gov API found on %s", endpoint)
+	}
+
+	caps.Authz, err = isRouteSupported(endpoint + "/cosmos/authz/v1beta1/grants")
+	if err != nil {
+		return caps, err
+	}
+
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/base/tendermint/v1beta1/node_info",
+		Method:   http.MethodGet,
+	})
+	if err == nil && resp.StatusCode == http.StatusOK {
+		var info nodeInfo
+		if err := json.Unmarshal(resp.Body, &info); err == nil {
+			caps.SDKVersion = info.ApplicationVersion.CosmosSDKVersion
+		}
+	}
+
+	return caps, nil
+}
+
+// isRouteSupported returns whether the endpoint serves the route. Any other
+// status than the ones of routeSupported is returned as an error, so that a
+// failing endpoint does not change the cached capabilities.
+func isRouteSupported(url string) (bool, error) {
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: url,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	supported, err := routeSupported(resp.StatusCode)
+	if err != nil {
+		return false, fmt.Errorf("failed to probe %s: %v", url, err)
+	}
+
+	return supported, nil
+}
+
+// routeSupported maps the status of a probe to the support of the route.
+// Successful responses and bad requests, like missing query parameters, mean
+// the route is served, not found and not implemented mean it is not.
+func routeSupported(statusCode int) (bool, error) {
+	switch {
+	case statusCode >= 200 && statusCode < 300, statusCode == http.StatusBadRequest:
+		return true, nil
+	case statusCode == http.StatusNotFound, statusCode == http.StatusNotImplemented:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code %d", statusCode)
+	}
+}
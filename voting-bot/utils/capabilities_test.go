@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteSupported(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		supported  bool
+		wantErr    bool
+	}{
+		{statusCode: http.StatusOK, supported: true},
+		{statusCode: http.StatusBadRequest, supported: true},
+		{statusCode: http.StatusNotFound},
+		{statusCode: http.StatusNotImplemented},
+		{statusCode: http.StatusForbidden, wantErr: true},
+		{statusCode: http.StatusTooManyRequests, wantErr: true},
+		{statusCode: http.StatusInternalServerError, wantErr: true},
+		{statusCode: http.StatusBadGateway, wantErr: true},
+		{statusCode: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			supported, err := routeSupported(tt.statusCode)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unexpected status code")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.supported, supported)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// FeeAllowance is a fee allowance of a grantee with the nested allowances
+// flattened
+type FeeAllowance struct {
+	// SpendLimit is the remaining amount the grantee can spend, the
+	// allowance is unlimited if it is empty
+	SpendLimit sdk.Coins
+	Expiration *time.Time
+	// Periodic allowances limit the amount spent in every period
+	Periodic       bool
+	PeriodCanSpend sdk.Coins
+	PeriodReset    *time.Time
+	// AllowedMessages limits the messages the allowance pays for, all
+	// messages are allowed if it is empty
+	AllowedMessages []string
+}
+
+// GetFeeAllowance returns the fee allowance the granter has given to the
+// grantee, or nil if there is none.
+func GetFeeAllowance(endpoint, granter, grantee string) (*FeeAllowance, error) {
+	resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+		Endpoint: endpoint + "/cosmos/feegrant/v1beta1/allowance/" + granter + "/" + grantee,
+		Method:   http.MethodGet,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if strings.Contains(string(resp.Body), "not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get fee allowance: status code %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var allowanceResp types.FeeAllowanceResponse
+	if err := json.Unmarshal(resp.Body, &allowanceResp); err != nil {
+		return nil, err
+	}
+
+	var allowance FeeAllowance
+	if err := flattenFeeAllowance(&allowanceResp.Allowance.Allowance, &allowance); err != nil {
+		return nil, err
+	}
+
+	return &allowance, nil
+}
+
+func flattenFeeAllowance(in *types.FeeAllowance, out *FeeAllowance) error {
+	if in == nil {
+		return nil
+	}
+
+	out.AllowedMessages = append(out.AllowedMessages, in.AllowedMessages...)
+	if in.Expiration != nil {
+		out.Expiration = in.Expiration
+	}
+
+	if len(in.SpendLimit) > 0 {
+		coins, err := toCoins(in.SpendLimit)
+		if err != nil {
+			return err
+		}
+		out.SpendLimit = coins
+	}
+
+	if in.PeriodReset != nil {
+		coins, err := toCoins(in.PeriodCanSpend)
+		if err != nil {
+			return err
+		}
+		out.Periodic = true
+		out.PeriodCanSpend = coins
+		out.PeriodReset = in.PeriodReset
+	}
+
+	if err := flattenFeeAllowance(in.Basic, out); err != nil {
+		return err
+	}
+
+	return flattenFeeAllowance(in.Allowance, out)
+}
+
+func toCoins(in []types.Coin) (sdk.Coins, error) {
+	coins := sdk.NewCoins()
+	for _, coin := range in {
+		amount, ok := sdk.NewIntFromString(coin.Amount)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %s of %s", coin.Amount, coin.Denom)
+		}
+		coins = coins.Add(sdk.NewCoin(coin.Denom, amount))
+	}
+
+	return coins, nil
+}
+
+// Allows returns true if the allowance can pay the fees of a transaction of
+// the message at the given time
+func (a FeeAllowance) Allows(typeURL string, now time.Time) bool {
+	if a.Expiration != nil && !now.Before(*a.Expiration) {
+		return false
+	}
+
+	if a.SpendLimit != nil && a.SpendLimit.IsZero() {
+		return false
+	}
+
+	if a.Periodic && a.PeriodCanSpend.IsZero() && (a.PeriodReset == nil || now.Before(*a.PeriodReset)) {
+		return false
+	}
+
+	if len(a.AllowedMessages) == 0 {
+		return true
+	}
+
+	for _, msg := range a.AllowedMessages {
+		if msg == typeURL {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package voting
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/shomali11/slacker"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// txLinkPattern finds the hashes in the links to broadcasted transactions
+var txLinkPattern = regexp.MustCompile(`/txs/([0-9A-Fa-f]{64})`)
+
+// AddAuditEntry adds the command or the job action to the audit log
+func AddAuditEntry(ctx types.Context, entry database.AuditEntry) {
+	if err := ctx.Database().AddAuditEntry(entry); err != nil {
+		log.Printf("failed to store audit entry of %s: %v", entry.Command, err)
+	}
+}
+
+// AuditWriter passes the responses to the wrapped response writer and keeps
+// the reported errors and the broadcasted transactions, which make up the
+// result of the command or the job action in the audit log.
+type AuditWriter struct {
+	slacker.ResponseWriter
+	errs     []string
+	txHashes []string
+}
+
+// NewAuditWriter wraps the response writer
+func NewAuditWriter(w slacker.ResponseWriter) *AuditWriter {
+	return &AuditWriter{ResponseWriter: w}
+}
+
+func (w *AuditWriter) Post(channel, message string, options ...slacker.ReplyOption) error {
+	w.addTxHashes(message)
+	return w.ResponseWriter.Post(channel, message, options...)
+}
+
+func (w *AuditWriter) Reply(message string, options ...slacker.ReplyOption) error {
+	w.addTxHashes(message)
+	return w.ResponseWriter.Reply(message, options...)
+}
+
+func (w *AuditWriter) ReportError(err error, options ...slacker.ReportErrorOption) {
+	w.errs = append(w.errs, err.Error())
+	w.ResponseWriter.ReportError(err, options...)
+}
+
+// Entry completes the audit entry with the result and the transactions
+// reported so far
+func (w *AuditWriter) Entry(entry database.AuditEntry) database.AuditEntry {
+	entry.Result = database.AuditResultOK
+	if len(w.errs) > 0 {
+		entry.Result = fmt.Sprintf("%s: %s", database.AuditResultError, strings.Join(w.errs, "; "))
+	}
+	entry.TxHash = strings.Join(w.txHashes, ",")
+
+	return entry
+}
+
+// AddTxLinks keeps the transactions linked in a result which is not posted
+// through the writer
+func (w *AuditWriter) AddTxLinks(message string) {
+	w.addTxHashes(message)
+}
+
+func (w *AuditWriter) addTxHashes(message string) {
+	for _, match := range txLinkPattern.FindAllStringSubmatch(message, -1) {
+		w.txHashes = append(w.txHashes, match[1])
+	}
+}
@@ -0,0 +1,135 @@
+package voting
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	v1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/shomali11/slacker"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+)
+
+// ProposalVote is the vote on one of the proposals of a batch vote
+type ProposalVote struct {
+	ProposalID uint64
+	Option     v1.VoteOption
+}
+
+// ParseBatchVotes parses comma separated proposalId=option pairs, e.g.
+// "12=yes,13=no". Every proposal may appear once.
+func ParseBatchVotes(str string) ([]ProposalVote, error) {
+	var votes []ProposalVote
+	seen := make(map[uint64]bool)
+	for _, pair := range strings.Split(str, ",") {
+		fields := strings.Split(strings.TrimSpace(pair), "=")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid batch vote %q, expected proposalId=option", pair)
+		}
+
+		proposalID, err := strconv.ParseUint(strings.TrimSpace(fields[0]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proposal id %q: %v", fields[0], err)
+		}
+
+		if seen[proposalID] {
+			return nil, fmt.Errorf("proposal %d is voted on more than once", proposalID)
+		}
+		seen[proposalID] = true
+
+		option, err := stringToVoteOption(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		votes = append(votes, ProposalVote{
+			ProposalID: proposalID,
+			Option:     option,
+		})
+	}
+
+	return votes, nil
+}
+
+// BatchProposalIDs returns the comma separated proposal ids of a batch vote
+func BatchProposalIDs(votes []ProposalVote) string {
+	var ids []string
+	for _, vote := range votes {
+		ids = append(ids, strconv.FormatUint(vote.ProposalID, 10))
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// ExecBatchVote votes on several proposals on behalf of the validator in a
+// single authz MsgExec, so only one transaction is signed and paid for.
+// The vote of every proposal is confirmed and logged on its own in the
+// background.
+func ExecBatchVote(ctx types.Context, chainName, valAddr, batchVotes,
+	fromKey, memo, gasPrices string, dryRun bool, responseWriter slacker.ResponseWriter,
+) (string, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			responseWriter.Reply(fmt.Sprintf("Recovered from panic: %v", r))
+			log.Println("Recovered from panic:", r)
+		}
+	}()
+
+	votes, err := ParseBatchVotes(batchVotes)
+	if err != nil {
+		return "", err
+	}
+
+	granter, err := utils.ConvertValAddrToAccAddr(ctx, valAddr, chainName)
+	if err != nil {
+		return "", fmt.Errorf("error while decoding validator address %s: %v", valAddr, err)
+	}
+
+	session, err := newVoteSession(ctx, chainName, granter, fromKey, gasPrices, &v1.MsgVote{}, &v1beta1.MsgVote{})
+	if err != nil {
+		return "", err
+	}
+
+	msgs := make([]sdk.Msg, len(votes))
+	for i, vote := range votes {
+		if session.isV1 {
+			msgs[i] = &v1.MsgVote{
+				ProposalId: vote.ProposalID,
+				Voter:      granter,
+				Option:     vote.Option,
+			}
+		} else {
+			msgs[i] = &v1beta1.MsgVote{
+				ProposalId: vote.ProposalID,
+				Voter:      granter,
+				Option:     v1beta1.VoteOption(vote.Option),
+			}
+		}
+	}
+
+	if err := session.simulate(chainName, valAddr, responseWriter, msgs...); err != nil {
+		return "", err
+	}
+
+	if dryRun {
+		return dryRunResult, nil
+	}
+
+	responseWriter.Reply(fmt.Sprintf("voting %s on %s proposals for %s", batchVotes, chainName, valAddr))
+	res, result, err := session.send(chainName, memo, msgs...)
+	if err != nil {
+		return "", err
+	}
+
+	pending := make([]pendingVote, len(votes))
+	for i, vote := range votes {
+		pending[i] = pendingVote{strconv.FormatUint(vote.ProposalID, 10), vote.Option.String()}
+	}
+	session.confirmInBackground(ctx, chainName, valAddr, res.TxHash, result, pending, responseWriter)
+
+	return result + "\n" + confirmingResult, nil
+}
@@ -0,0 +1,62 @@
+package voting
+
+import (
+	"testing"
+
+	v1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBatchVotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []ProposalVote
+		wantErr string
+	}{
+		{
+			name:  "two proposals",
+			input: "12=yes,13=no",
+			want:  []ProposalVote{{ProposalID: 12, Option: v1.OptionYes}, {ProposalID: 13, Option: v1.OptionNo}},
+		},
+		{
+			name:  "spaces and upper case",
+			input: " 12 = YES , 13=No_With_Veto ",
+			want:  []ProposalVote{{ProposalID: 12, Option: v1.OptionYes}, {ProposalID: 13, Option: v1.OptionNoWithVeto}},
+		},
+		{
+			name:  "single proposal",
+			input: "7=abstain",
+			want:  []ProposalVote{{ProposalID: 7, Option: v1.OptionAbstain}},
+		},
+		{name: "duplicate proposal", input: "12=yes,12=no", wantErr: "voted on more than once"},
+		{name: "missing option", input: "12", wantErr: "expected proposalId=option"},
+		{name: "extra separator", input: "12=yes=no", wantErr: "expected proposalId=option"},
+		{name: "trailing comma", input: "12=yes,", wantErr: "expected proposalId=option"},
+		{name: "invalid proposal id", input: "abc=yes", wantErr: "invalid proposal id"},
+		{name: "negative proposal id", input: "-1=yes", wantErr: "invalid proposal id"},
+		{name: "weighted option", input: "12=yes:0.5", wantErr: "invalid vote option"},
+		{name: "unknown option", input: "12=maybe", wantErr: "invalid vote option"},
+		{name: "empty", input: "", wantErr: "expected proposalId=option"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			votes, err := ParseBatchVotes(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, votes)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, votes)
+		})
+	}
+}
+
+func TestBatchProposalIDs(t *testing.T) {
+	votes := []ProposalVote{{ProposalID: 12, Option: v1.OptionYes}, {ProposalID: 13, Option: v1.OptionNo}}
+	assert.Equal(t, "12,13", BatchProposalIDs(votes))
+	assert.Equal(t, "", BatchProposalIDs(nil))
+}
@@ -0,0 +1,234 @@
+package voting
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// Action ids of the buttons confirming or cancelling a simulated vote, their
+// value is the id of the vote request
+const (
+	ConfirmVoteActionID = "confirm_vote"
+	CancelVoteActionID  = "cancel_vote"
+)
+
+// confirmationTimeout is the time the requester has to confirm a simulated
+// vote
+const confirmationTimeout = 15 * time.Minute
+
+// SubmitVote simulates the vote and reports the estimated gas and fee, a dry
+// run ends there. Otherwise the vote is stored as a vote request, which is
+// broadcasted once the requester confirms it or, if approvals are required,
+// once other users approve it. The id of the stored request is returned,
+// errors are reported to the response writer and returned.
+func SubmitVote(ctx types.Context, req database.VoteRequest, dryRun bool, responseWriter slacker.ResponseWriter) (int64, error) {
+	if dryRun {
+		if err := ExecVoteRequest(ctx, req, true, responseWriter); err != nil {
+			responseWriter.ReportError(err)
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if err := ValidateVoteRequest(ctx, req); err != nil {
+		responseWriter.ReportError(err)
+		return 0, err
+	}
+
+	if err := SimulateVoteRequest(ctx, req, responseWriter); err != nil {
+		responseWriter.ReportError(err)
+		return 0, err
+	}
+
+	approval := ctx.Config().Approval
+	if approval.RequiredApprovals == 0 {
+		req.Status = database.VoteRequestUnconfirmed
+		id, err := ctx.Database().AddVoteRequest(req, confirmationTimeout)
+		if err != nil {
+			err = fmt.Errorf("failed to store vote request: %v", err)
+			responseWriter.ReportError(err)
+			return 0, err
+		}
+
+		AuditVoteRequest(ctx, id, req.RequestedBy, "simulated", DescribeVoteRequest(req))
+		text := fmt.Sprintf("<@%s>, confirm %s within %s to broadcast it", req.RequestedBy, DescribeVoteRequest(req), confirmationTimeout)
+		responseWriter.Reply(text, slacker.WithBlocks(ConfirmationBlocks(id, text)))
+		return id, nil
+	}
+
+	id, err := ctx.Database().AddVoteRequest(req, approval.Timeout)
+	if err != nil {
+		err = fmt.Errorf("failed to store vote request: %v", err)
+		responseWriter.ReportError(err)
+		return 0, err
+	}
+
+	AuditVoteRequest(ctx, id, req.RequestedBy, "requested", DescribeVoteRequest(req))
+	responseWriter.Reply(fmt.Sprintf("Vote request *#%d* created by <@%s>: %s.\n%d approval(s) are required within %s, approve with `approve-vote %d` or reject with `reject-vote %d`.",
+		id, req.RequestedBy, DescribeVoteRequest(req), approval.RequiredApprovals, approval.Timeout, id, id))
+	return id, nil
+}
+
+// ConfirmationBlocks returns the text with the buttons confirming or
+// cancelling the simulated vote of the request
+func ConfirmationBlocks(id int64, text string) []slack.Block {
+	value := strconv.FormatInt(id, 10)
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+		slack.NewActionBlock(ConfirmationBlockID(id),
+			slack.NewButtonBlockElement(ConfirmVoteActionID, value, slack.NewTextBlockObject("plain_text", "Confirm", false, false)).WithStyle(slack.StylePrimary),
+			slack.NewButtonBlockElement(CancelVoteActionID, value, slack.NewTextBlockObject("plain_text", "Cancel", false, false)),
+		),
+	}
+}
+
+// ConfirmationBlockID identifies the confirmation buttons of a vote request
+func ConfirmationBlockID(id int64) string {
+	return fmt.Sprintf("vote_confirmation_%d", id)
+}
+
+// ValidateVoteRequest checks the targets and the vote options of a vote
+// before it is stored to be executed later.
+func ValidateVoteRequest(ctx types.Context, req database.VoteRequest) error {
+	if _, _, _, err := GetVoteTargets(ctx, req.Target); err != nil {
+		return err
+	}
+
+	switch req.VoteType {
+	case database.VoteTypeWeighted:
+		_, err := ParseWeightedVoteOptions(req.VoteOption)
+		return err
+	case database.VoteTypeBatch:
+		_, err := ParseBatchVotes(req.VoteOption)
+		return err
+	}
+
+	return ValidateVoteOption(req.VoteOption)
+}
+
+// ExecVoteRequest votes on the proposal for all validators targeted by the
+// request. Failed votes of single validators are reported and the remaining
+// validators still vote, an error is returned if any of the votes failed.
+func ExecVoteRequest(ctx types.Context, req database.VoteRequest, dryRun bool, responseWriter slacker.ResponseWriter) error {
+	chainName, validators, fromKey, err := GetVoteTargets(ctx, req.Target)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, valAddr := range validators {
+		result, err := execVote(ctx, req, chainName, valAddr, fromKey, dryRun, responseWriter)
+		if err != nil {
+			log.Printf("error on executing %s for %s: %v", req.VoteType, valAddr, err)
+			responseWriter.ReportError(fmt.Errorf("error on executing %s for %s: %v", req.VoteType, valAddr, err))
+			failed++
+			continue
+		}
+
+		responseWriter.Reply(result)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %d of %d validators", req.VoteType, failed, len(validators))
+	}
+
+	return nil
+}
+
+// SimulateVoteRequest simulates the vote of every validator targeted by the
+// request and reports the estimated gas and fee. An error is returned if any
+// of the votes would fail.
+func SimulateVoteRequest(ctx types.Context, req database.VoteRequest, responseWriter slacker.ResponseWriter) error {
+	chainName, validators, fromKey, err := GetVoteTargets(ctx, req.Target)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, valAddr := range validators {
+		if _, err := execVote(ctx, req, chainName, valAddr, fromKey, true, responseWriter); err != nil {
+			log.Printf("simulation of %s for %s failed: %v", req.VoteType, valAddr, err)
+			responseWriter.ReportError(err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s would fail for %d of %d validators, it was not stored", req.VoteType, failed, len(validators))
+	}
+
+	return nil
+}
+
+// execVote runs the vote of the request for a single validator
+func execVote(ctx types.Context, req database.VoteRequest, chainName, valAddr, fromKey string, dryRun bool,
+	responseWriter slacker.ResponseWriter,
+) (string, error) {
+	switch req.VoteType {
+	case database.VoteTypeWeighted:
+		return ExecWeightedVote(ctx, chainName, req.ProposalID, valAddr, req.VoteOption, fromKey, req.Metadata, req.Memo, req.GasPrices, dryRun, responseWriter)
+	case database.VoteTypeBatch:
+		return ExecBatchVote(ctx, chainName, valAddr, req.VoteOption, fromKey, req.Memo, req.GasPrices, dryRun, responseWriter)
+	default:
+		return ExecVote(ctx, chainName, req.ProposalID, valAddr, req.VoteOption, fromKey, req.Metadata, req.Memo, req.GasPrices, dryRun, responseWriter)
+	}
+}
+
+// GetVoteTargets resolves the chain name or validator address given to the
+// vote commands into the chain, the validators to vote for and the voting key
+// of the chain. A chain name selects all validators registered on the chain.
+func GetVoteTargets(ctx types.Context, target string) (string, []string, string, error) {
+	db := ctx.Database()
+
+	var chainName string
+	var validators []string
+	if db.HasValidator(target) {
+		val, err := db.GetValidator(target)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("failed to get validator from the database: %v", err)
+		}
+
+		chainName = val.ChainName
+		validators = []string{val.Address}
+	} else {
+		addresses, err := db.GetChainValidators(target)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("failed to get validator address from the database: %v", err)
+		}
+
+		if len(addresses) == 0 {
+			return "", nil, "", fmt.Errorf("no validator is registered for chain %s", target)
+		}
+
+		chainName = target
+		validators = addresses
+	}
+
+	fromKey, err := db.GetChainKey(chainName, "voting")
+	if err != nil {
+		return "", nil, "", fmt.Errorf("error while getting key address of chain %s", chainName)
+	}
+
+	return chainName, validators, fromKey, nil
+}
+
+func DescribeVoteRequest(req database.VoteRequest) string {
+	if req.VoteType == database.VoteTypeBatch {
+		return fmt.Sprintf("%s %s for %s", req.VoteType, req.VoteOption, req.Target)
+	}
+	return fmt.Sprintf("%s %s on proposal %s for %s", req.VoteType, req.VoteOption, req.ProposalID, req.Target)
+}
+
+// AuditVoteRequest adds an entry to the audit trail of the vote request
+func AuditVoteRequest(ctx types.Context, id int64, userID, action, details string) {
+	if err := ctx.Database().AddVoteAudit(id, userID, action, details); err != nil {
+		log.Printf("failed to store audit of vote request %d: %v", id, err)
+	}
+}
@@ -0,0 +1,75 @@
+package voting
+
+import (
+	"fmt"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// channelResponseWriter posts the responses of votes which are not run by a
+// Slack command, such as scheduled votes, to the configured channel. Thread
+// replies are posted in the thread of threadTS if it is set, all replies are
+// if inThread is set.
+type channelResponseWriter struct {
+	ctx       types.Context
+	channelID string
+	threadTS  string
+	inThread  bool
+}
+
+// NewChannelResponseWriter returns a response writer which posts to the
+// channel of the config
+func NewChannelResponseWriter(ctx types.Context, threadTS string) slacker.ResponseWriter {
+	return &channelResponseWriter{
+		ctx:       ctx,
+		channelID: ctx.Config().Slack.ChannelID,
+		threadTS:  threadTS,
+	}
+}
+
+// NewThreadResponseWriter returns a response writer which posts all replies
+// in the thread of the message
+func NewThreadResponseWriter(ctx types.Context, channelID, threadTS string) slacker.ResponseWriter {
+	return &channelResponseWriter{
+		ctx:       ctx,
+		channelID: channelID,
+		threadTS:  threadTS,
+		inThread:  true,
+	}
+}
+
+func (w *channelResponseWriter) Post(channel, message string, options ...slacker.ReplyOption) error {
+	defaults := slacker.NewReplyDefaults(options...)
+
+	opts := []slack.MsgOption{
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionAttachments(defaults.Attachments...),
+		slack.MsgOptionBlocks(defaults.Blocks...),
+	}
+
+	if (defaults.ThreadResponse || w.inThread) && w.threadTS != "" {
+		opts = append(opts, slack.MsgOptionTS(w.threadTS))
+	}
+
+	_, _, err := w.ctx.Slacker().APIClient().PostMessage(channel, opts...)
+	return err
+}
+
+func (w *channelResponseWriter) Reply(message string, options ...slacker.ReplyOption) error {
+	return w.Post(w.channelID, message, options...)
+}
+
+func (w *channelResponseWriter) ReportError(err error, options ...slacker.ReportErrorOption) {
+	defaults := slacker.NewReportErrorDefaults(options...)
+
+	var replyOptions []slacker.ReplyOption
+	if defaults.ThreadResponse {
+		replyOptions = append(replyOptions, slacker.WithThreadReply(true))
+	}
+
+	if err := w.Reply(fmt.Sprintf("*Error:* _%s_", err.Error()), replyOptions...); err != nil {
+		fmt.Printf("failed posting message: %v\n", err)
+	}
+}
@@ -0,0 +1,75 @@
+package voting
+
+import (
+	"strings"
+
+	"github.com/vitwit/authz-apps/voting-bot/config"
+)
+
+// RuleProposal holds the fields of a proposal which voting rules are matched
+// against
+type RuleProposal struct {
+	ChainName    string
+	Title        string
+	Proposer     string
+	MessageTypes []string
+}
+
+// MatchVotingRule returns the first of the rules matching the proposal
+func MatchVotingRule(rules []config.VotingRuleConfig, proposal RuleProposal) (config.VotingRuleConfig, bool) {
+	for _, rule := range rules {
+		if matchesRule(rule, proposal) {
+			return rule, true
+		}
+	}
+
+	return config.VotingRuleConfig{}, false
+}
+
+func matchesRule(rule config.VotingRuleConfig, proposal RuleProposal) bool {
+	if len(rule.ChainNames) > 0 && !containsAny(rule.ChainNames, proposal.ChainName) {
+		return false
+	}
+
+	if len(rule.MessageTypes) > 0 && !containsAny(rule.MessageTypes, proposal.MessageTypes...) {
+		return false
+	}
+
+	if len(rule.Proposers) > 0 && !containsAny(rule.Proposers, proposal.Proposer) {
+		return false
+	}
+
+	if len(rule.TitleKeywords) > 0 {
+		title := strings.ToLower(proposal.Title)
+		matched := false
+		for _, keyword := range rule.TitleKeywords {
+			if strings.Contains(title, strings.ToLower(keyword)) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsAny returns true if any of the values is in the list
+func containsAny(list []string, values ...string) bool {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		for _, item := range list {
+			if item == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
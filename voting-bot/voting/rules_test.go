@@ -0,0 +1,99 @@
+package voting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitwit/authz-apps/voting-bot/config"
+)
+
+func TestMatchVotingRule(t *testing.T) {
+	rules := []config.VotingRuleConfig{
+		{
+			Name:         "ibc-client-recovery",
+			ChainNames:   []string{"cosmoshub", "osmosis"},
+			MessageTypes: []string{"/ibc.core.client.v1.MsgRecoverClient", "/ibc.core.client.v1.ClientUpdateProposal"},
+			VoteOption:   "yes",
+		},
+		{
+			Name:          "trusted-proposer",
+			Proposers:     []string{"cosmos1trusted"},
+			TitleKeywords: []string{"Parameter Change", "upgrade"},
+			VoteOption:    "yes",
+		},
+		{
+			Name:          "spam",
+			ChainNames:    []string{"juno"},
+			TitleKeywords: []string{"airdrop"},
+			VoteOption:    "no_with_veto",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		proposal RuleProposal
+		want     string
+	}{
+		{
+			name:     "message type on listed chain",
+			proposal: RuleProposal{ChainName: "osmosis", MessageTypes: []string{"/cosmos.bank.v1beta1.MsgSend", "/ibc.core.client.v1.MsgRecoverClient"}},
+			want:     "ibc-client-recovery",
+		},
+		{
+			name:     "legacy content type",
+			proposal: RuleProposal{ChainName: "cosmoshub", MessageTypes: []string{"/ibc.core.client.v1.ClientUpdateProposal"}},
+			want:     "ibc-client-recovery",
+		},
+		{
+			name:     "message type on other chain",
+			proposal: RuleProposal{ChainName: "juno", MessageTypes: []string{"/ibc.core.client.v1.MsgRecoverClient"}},
+		},
+		{
+			name:     "keyword matched case insensitively",
+			proposal: RuleProposal{ChainName: "juno", Proposer: "cosmos1trusted", Title: "v15 UPGRADE"},
+			want:     "trusted-proposer",
+		},
+		{
+			name:     "proposer without keyword",
+			proposal: RuleProposal{ChainName: "juno", Proposer: "cosmos1trusted", Title: "Community pool spend"},
+		},
+		{
+			name:     "keyword from other proposer",
+			proposal: RuleProposal{ChainName: "cosmoshub", Proposer: "cosmos1other", Title: "Parameter change"},
+		},
+		{
+			name:     "missing proposer",
+			proposal: RuleProposal{ChainName: "cosmoshub", Title: "Parameter change"},
+		},
+		{
+			name:     "first matching rule wins",
+			proposal: RuleProposal{ChainName: "cosmoshub", Proposer: "cosmos1trusted", Title: "Upgrade", MessageTypes: []string{"/ibc.core.client.v1.MsgRecoverClient"}},
+			want:     "ibc-client-recovery",
+		},
+		{
+			name:     "keyword inside title",
+			proposal: RuleProposal{ChainName: "juno", Title: "Claim your AIRDROP now"},
+			want:     "spam",
+		},
+		{
+			name:     "no rule",
+			proposal: RuleProposal{ChainName: "cosmoshub", Title: "Community pool spend", MessageTypes: []string{"/cosmos.distribution.v1beta1.MsgCommunityPoolSpend"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, found := MatchVotingRule(rules, tt.proposal)
+			assert.Equal(t, tt.want != "", found)
+			assert.Equal(t, tt.want, rule.Name)
+		})
+	}
+
+	_, found := MatchVotingRule(nil, RuleProposal{ChainName: "cosmoshub"})
+	assert.False(t, found)
+
+	// a rule without conditions matches every proposal
+	rule, found := MatchVotingRule([]config.VotingRuleConfig{{Name: "default", VoteOption: "abstain"}}, RuleProposal{ChainName: "cosmoshub"})
+	assert.True(t, found)
+	assert.Equal(t, "default", rule.Name)
+}
@@ -0,0 +1,149 @@
+package voting
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/types"
+)
+
+// ProposalStatusBlockID identifies the status block of the first alert on a
+// proposal, which is replaced when the status changes
+const ProposalStatusBlockID = "proposal_status"
+
+// ProposalThreadWriter returns a response writer which posts in the thread
+// of the proposal, or to the channel if the proposal has no thread
+func ProposalThreadWriter(ctx types.Context, chainName, proposalID string) slacker.ResponseWriter {
+	thread, found, err := ctx.Database().GetProposalThread(chainName, proposalID)
+	if err != nil {
+		log.Printf("failed to get thread of %s proposal %s: %v", chainName, proposalID, err)
+	}
+	if !found {
+		return NewChannelResponseWriter(ctx, "")
+	}
+
+	return NewThreadResponseWriter(ctx, thread.ChannelID, thread.ThreadTS)
+}
+
+// PostInProposalThread replies in the thread of the proposal and updates the
+// status of its first alert. It returns false if the proposal has no thread.
+func PostInProposalThread(ctx types.Context, chainName, proposalID, text string) (bool, error) {
+	thread, found, err := ctx.Database().GetProposalThread(chainName, proposalID)
+	if err != nil || !found {
+		return false, err
+	}
+
+	_, _, err = ctx.Slacker().APIClient().PostMessage(
+		thread.ChannelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionTS(thread.ThreadTS),
+	)
+	if err != nil {
+		return true, fmt.Errorf("failed to post in thread of %s proposal %s: %v", chainName, proposalID, err)
+	}
+
+	return true, UpdateProposalThread(ctx, chainName, proposalID)
+}
+
+// UpdateProposalThread replaces the status block of the first alert on the
+// proposal with its current status and the votes of the validators
+func UpdateProposalThread(ctx types.Context, chainName, proposalID string) error {
+	db := ctx.Database()
+	thread, found, err := db.GetProposalThread(chainName, proposalID)
+	if err != nil || !found {
+		return err
+	}
+
+	votes, err := db.GetProposalVotes(chainName, proposalID)
+	if err != nil {
+		return fmt.Errorf("failed to get votes on %s proposal %s: %v", chainName, proposalID, err)
+	}
+
+	api := ctx.Slacker().APIClient()
+	history, err := api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+		ChannelID: thread.ChannelID,
+		Latest:    thread.ThreadTS,
+		Oldest:    thread.ThreadTS,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get the alert of %s proposal %s: %v", chainName, proposalID, err)
+	}
+	if len(history.Messages) == 0 {
+		return fmt.Errorf("the alert of %s proposal %s was deleted", chainName, proposalID)
+	}
+
+	status := ProposalStatusBlock(thread, votes)
+	replaced := false
+	var blocks []slack.Block
+	for _, block := range history.Messages[0].Blocks.BlockSet {
+		if context, ok := block.(*slack.ContextBlock); ok && context.BlockID == ProposalStatusBlockID {
+			block, replaced = status, true
+		}
+		blocks = append(blocks, block)
+	}
+	if !replaced {
+		blocks = append(blocks, status)
+	}
+
+	_, _, _, err = api.UpdateMessage(
+		thread.ChannelID,
+		thread.ThreadTS,
+		slack.MsgOptionText(history.Messages[0].Text, false),
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update the alert of %s proposal %s: %v", chainName, proposalID, err)
+	}
+
+	return nil
+}
+
+// ProposalStatusBlock shows the status of the proposal and the votes of the
+// validators in the first alert on the proposal
+func ProposalStatusBlock(thread database.ProposalThread, votes []database.ProposalVote) *slack.ContextBlock {
+	status := thread.Status
+	if status == database.ThreadVotingPeriod {
+		if endTime, err := time.Parse(time.RFC3339, thread.VotingEndTime); err == nil {
+			status += ", ends " + endTime.UTC().Format(time.RFC822)
+		}
+	}
+
+	lines := []string{fmt.Sprintf("*Status:* %s", status)}
+	for _, vote := range votes {
+		switch {
+		case vote.VoteOption == "":
+			lines = append(lines, fmt.Sprintf(":hourglass: %s has not voted", vote.ValidatorAddress))
+		case vote.Confirmed:
+			lines = append(lines, fmt.Sprintf(":white_check_mark: %s voted *%s*", vote.ValidatorAddress, FormatVoteOption(vote.VoteOption)))
+		default:
+			lines = append(lines, fmt.Sprintf(":ballot_box_with_ballot: %s voted *%s*", vote.ValidatorAddress, FormatVoteOption(vote.VoteOption)))
+		}
+	}
+
+	return slack.NewContextBlock(ProposalStatusBlockID, slack.NewTextBlockObject("mrkdwn", strings.Join(lines, "\n"), false, false))
+}
+
+// notifyVoteConfirmed posts the confirmed vote in the thread of the proposal.
+// It returns false if the proposal has no thread.
+func notifyVoteConfirmed(ctx types.Context, chainName, valAddr, proposalID, voteLog, result string) bool {
+	text := fmt.Sprintf(":white_check_mark: Vote *%s* of %s confirmed on chain\n%s", FormatVoteOption(voteLog), valAddr, result)
+	posted, err := PostInProposalThread(ctx, chainName, proposalID, text)
+	if err != nil {
+		log.Printf("failed to post confirmed vote: %v", err)
+	}
+
+	return posted
+}
+
+// FormatVoteOption shortens the vote options of the vote logs, e.g.
+// VOTE_OPTION_NO_WITH_VETO to no_with_veto
+func FormatVoteOption(option string) string {
+	return strings.ToLower(strings.ReplaceAll(option, "VOTE_OPTION_", ""))
+}
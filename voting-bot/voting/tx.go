@@ -0,0 +1,91 @@
+package voting
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	lensclient "github.com/strangelove-ventures/lens/client"
+	"github.com/vitwit/authz-apps/voting-bot/utils"
+)
+
+// FeeGranter returns the granter if it has given the grantee a fee allowance
+// which can pay for authz executions, otherwise it returns an empty string
+// and the grantee pays the fees itself.
+func FeeGranter(endpoint, granter, grantee string) string {
+	allowance, err := utils.GetFeeAllowance(endpoint, granter, grantee)
+	if err != nil {
+		log.Printf("failed to get fee allowance of %s from %s: %v", grantee, granter, err)
+		return ""
+	}
+
+	if allowance == nil || !allowance.Allows(sdk.MsgTypeURL(&authz.MsgExec{}), time.Now().UTC()) {
+		return ""
+	}
+
+	return granter
+}
+
+// SendMsgs signs and broadcasts the messages like the SendMsgs of the chain
+// client. If feeGranter is set the fees are paid from its fee allowance.
+func SendMsgs(chainClient *lensclient.ChainClient, msgs []sdk.Msg, memo, feeGranter string) (*sdk.TxResponse, error) {
+	if feeGranter == "" {
+		return chainClient.SendMsgs(context.Background(), msgs, memo)
+	}
+
+	granter, err := sdk.GetFromBech32(feeGranter, chainClient.Config.AccountPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee granter %s: %v", feeGranter, err)
+	}
+
+	txf, err := chainClient.PrepareFactory(chainClient.TxFactory())
+	if err != nil {
+		return nil, err
+	}
+
+	_, adjusted, err := chainClient.CalculateGas(context.Background(), txf, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txf = txf.WithGas(adjusted).WithMemo(memo).WithFeeGranter(sdk.AccAddress(granter))
+
+	// the fee granter is encoded with the bech32 prefix of the sdk config,
+	// which is only set for the chain while the context is held
+	var txb client.TxBuilder
+	err = func() error {
+		done := chainClient.SetSDKContext()
+		defer done()
+
+		txb, err = txf.BuildUnsignedTx(msgs...)
+		if err != nil {
+			return err
+		}
+
+		return tx.Sign(txf, chainClient.Config.Key, txb, false)
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := chainClient.Codec.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := chainClient.BroadcastTx(context.Background(), txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Code != 0 {
+		return res, fmt.Errorf("transaction failed with code: %d", res.Code)
+	}
+
+	return res, nil
+}
@@ -2,10 +2,10 @@ package voting
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,6 +15,8 @@ import (
 	"github.com/shomali11/slacker"
 	lensclient "github.com/strangelove-ventures/lens/client"
 	registry "github.com/strangelove-ventures/lens/client/chain_registry"
+	"github.com/vitwit/authz-apps/voting-bot/database"
+	"github.com/vitwit/authz-apps/voting-bot/endpoints"
 	"github.com/vitwit/authz-apps/voting-bot/types"
 	"github.com/vitwit/authz-apps/voting-bot/utils"
 	"go.uber.org/zap"
@@ -45,9 +47,11 @@ func GetChainDenom(chainInfo registry.ChainInfo) (string, error) {
 	}
 }
 
-// Votes on the proposal using the given data and key
-func ExecVote(ctx types.Context, chainName, pID, granter, vote,
-	fromKey, metadata, memo, gasPrices string, responseWriter slacker.ResponseWriter,
+// Votes on the proposal on behalf of the validator using the given data and key.
+// The vote is simulated first and the estimated gas and fee are reported, with
+// dryRun set the vote is not broadcasted.
+func ExecVote(ctx types.Context, chainName, pID, valAddr, vote,
+	fromKey, metadata, memo, gasPrices string, dryRun bool, responseWriter slacker.ResponseWriter,
 ) (string, error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -56,21 +60,168 @@ func ExecVote(ctx types.Context, chainName, pID, granter, vote,
 		}
 	}()
 
+	proposalID, err := strconv.ParseUint(pID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert string to uint64. Err: %v", err)
+	}
+	voteOption, err := stringToVoteOption(vote)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert vote option string to sdk vote option. Err: %v", err)
+	}
+
+	granter, err := utils.ConvertValAddrToAccAddr(ctx, valAddr, chainName)
+	if err != nil {
+		return "", fmt.Errorf("error while decoding validator address %s: %v", valAddr, err)
+	}
+
+	session, err := newVoteSession(ctx, chainName, granter, fromKey, gasPrices, &v1.MsgVote{}, &v1beta1.MsgVote{})
+	if err != nil {
+		return "", err
+	}
+
+	var msg sdk.Msg
+	if session.isV1 {
+		msg = &v1.MsgVote{
+			ProposalId: proposalID,
+			Voter:      granter,
+			Option:     voteOption,
+			Metadata:   metadata,
+		}
+	} else {
+		if metadata != "" {
+			responseWriter.Reply(fmt.Sprintf("%s key is only authorized for gov v1beta1 votes, metadata will not be included in the vote", fromKey))
+		}
+
+		msg = &v1beta1.MsgVote{
+			ProposalId: proposalID,
+			Voter:      granter,
+			Option:     v1beta1.VoteOption(voteOption),
+		}
+	}
+
+	if err := session.simulate(chainName, valAddr, responseWriter, msg); err != nil {
+		return "", err
+	}
+
+	if dryRun {
+		return dryRunResult, nil
+	}
+
+	responseWriter.Reply(fmt.Sprintf("voting %s on %s proposal %d for %s", voteOption, chainName, proposalID, valAddr))
+	return session.broadcast(ctx, chainName, valAddr, pID, voteOption.String(), memo, msg, responseWriter)
+}
+
+// ExecWeightedVote splits the validator vote on the proposal across several
+// options. weightedOptions is a comma separated list of option=weight pairs
+// whose weights must add up to 1, e.g. "yes=0.7,abstain=0.3".
+func ExecWeightedVote(ctx types.Context, chainName, pID, valAddr, weightedOptions,
+	fromKey, metadata, memo, gasPrices string, dryRun bool, responseWriter slacker.ResponseWriter,
+) (string, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			responseWriter.Reply(fmt.Sprintf("Recovered from panic: %v", r))
+			log.Println("Recovered from panic:", r)
+		}
+	}()
+
+	proposalID, err := strconv.ParseUint(pID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("unable to convert string to uint64. Err: %v", err)
+	}
+	options, err := ParseWeightedVoteOptions(weightedOptions)
+	if err != nil {
+		return "", err
+	}
+
+	granter, err := utils.ConvertValAddrToAccAddr(ctx, valAddr, chainName)
+	if err != nil {
+		return "", fmt.Errorf("error while decoding validator address %s: %v", valAddr, err)
+	}
+
+	session, err := newVoteSession(ctx, chainName, granter, fromKey, gasPrices, &v1.MsgVoteWeighted{}, &v1beta1.MsgVoteWeighted{})
+	if err != nil {
+		return "", err
+	}
+
+	var msg sdk.Msg
+	if session.isV1 {
+		msg = &v1.MsgVoteWeighted{
+			ProposalId: proposalID,
+			Voter:      granter,
+			Options:    options,
+			Metadata:   metadata,
+		}
+	} else {
+		if metadata != "" {
+			responseWriter.Reply(fmt.Sprintf("%s key is only authorized for gov v1beta1 votes, metadata will not be included in the vote", fromKey))
+		}
+
+		legacyOptions := make(v1beta1.WeightedVoteOptions, len(options))
+		for i, option := range options {
+			legacyOptions[i] = v1beta1.WeightedVoteOption{
+				Option: v1beta1.VoteOption(option.Option),
+				Weight: sdk.MustNewDecFromStr(option.Weight),
+			}
+		}
+
+		msg = &v1beta1.MsgVoteWeighted{
+			ProposalId: proposalID,
+			Voter:      granter,
+			Options:    legacyOptions,
+		}
+	}
+
+	if err := session.simulate(chainName, valAddr, responseWriter, msg); err != nil {
+		return "", err
+	}
+
+	if dryRun {
+		return dryRunResult, nil
+	}
+
+	// the chain reports a vote with a single option without its weight
+	voteLog := FormatWeightedVote(toVoteOptions(options))
+	if len(options) == 1 {
+		voteLog = options[0].Option.String()
+	}
+
+	responseWriter.Reply(fmt.Sprintf("voting %s on %s proposal %d for %s", voteLog, chainName, proposalID, valAddr))
+	return session.broadcast(ctx, chainName, valAddr, pID, voteLog, memo, msg, responseWriter)
+}
+
+// voteSession holds the chain client and the grantee used to vote on behalf
+// of a validator.
+type voteSession struct {
+	chainClient *lensclient.ChainClient
+	keyAddr     string
+	endpoint    string
+	isV1        bool
+	// feeGranter pays the fees of the votes if it has given the grantee a
+	// fee allowance
+	feeGranter string
+}
+
+// newVoteSession builds the chain client for the voting key and finds which
+// gov version the grantee is authorized to vote with. v1Msg and v1beta1Msg
+// are the messages whose grants are checked.
+func newVoteSession(ctx types.Context, chainName, granter, fromKey, gasPrices string,
+	v1Msg, v1beta1Msg sdk.Msg,
+) (*voteSession, error) {
 	// Fetch chain info from chain registry
 	chainInfo, err := GetChainInfo(ctx, chainName)
 	if err != nil {
-		return "", fmt.Errorf("chain info not found: %v", err)
+		return nil, fmt.Errorf("chain info not found: %v", err)
 	}
 
 	//	Use Chain info to select random endpoint
 	rpc, err := chainInfo.GetRandomRPCEndpoint(ctx.Context())
 	if err != nil {
-		return "", fmt.Errorf("failed to get random RPC endpoint on chain %s. Err: %v", chainInfo.ChainID, err)
+		return nil, fmt.Errorf("failed to get random RPC endpoint on chain %s. Err: %v", chainInfo.ChainID, err)
 	}
 
 	denom, err := GetChainDenom(chainInfo)
 	if err != nil {
-		return "", fmt.Errorf("failed to get denom from chain %s: %v", chainInfo.ChainID, err)
+		return nil, fmt.Errorf("failed to get denom from chain %s: %v", chainInfo.ChainID, err)
 	}
 	coins, err := sdk.ParseDecCoins(gasPrices)
 	if err != nil {
@@ -82,80 +233,119 @@ func ExecVote(ctx types.Context, chainName, pID, granter, vote,
 		}
 	}
 
-	chainConfig := utils.GetChainConfig(fromKey, chainInfo, gasPrices, rpc, 1.4)
+	chainConfig := utils.GetChainConfig(fromKey, chainInfo, gasPrices, rpc, ctx.Config().Gas.VoteAdjustment)
 	curDir, err := os.Getwd()
 	if err != nil {
-		return "", fmt.Errorf("error while getting current directory: %v", err)
+		return nil, fmt.Errorf("error while getting current directory: %v", err)
 	}
 
 	// Create client object to pull chain info
 	chainClient, err := lensclient.NewChainClient(zap.L(), &chainConfig, filepath.Join(curDir, "voting-keys"), os.Stdin, os.Stdout)
 	if err != nil {
-		return "", fmt.Errorf("failed to build new chain client for %s. Err: %v", chainInfo.ChainID, err)
+		return nil, fmt.Errorf("failed to build new chain client for %s. Err: %v", chainInfo.ChainID, err)
 	}
 
 	keyAddr, err := ctx.Database().GetAuthzKeyAddress(fromKey, "voting")
 	if err != nil {
-		return "", fmt.Errorf("error while getting address of %s key", fromKey)
+		return nil, fmt.Errorf("error while getting address of %s key", fromKey)
 	}
 
-	proposalID, err := strconv.ParseUint(pID, 10, 64)
+	validEndpoint, err := endpoints.GetValidEndpointForChain(chainName)
 	if err != nil {
-		return "", fmt.Errorf("unable to convert string to uint64. Err: %v", err)
+		return nil, fmt.Errorf("failed to get valid LCD endpoint for %s: %v", chainName, err)
 	}
-	voteOption, err := stringToVoteOption(vote)
+
+	caps, err := utils.GetChainCapabilities(ctx, chainName, validEndpoint)
 	if err != nil {
-		return "", fmt.Errorf("unable to convert vote option string to sdk vote option. Err: %v", err)
+		return nil, fmt.Errorf("failed to detect the gov and authz support of %s: %v", chainName, err)
 	}
 
-	var msgAny *cdctypes.Any
+	if !caps.Authz {
+		return nil, fmt.Errorf("%s does not support authz", chainName)
+	}
 
-	// TODO: handle v1beta1 and v1 types
-	// validV1Endpoint, err := v.GetValidV1Endpoint(chainInfo)
-	// if err != nil {
-	// 	return fmt.Errorf("error while getting valid gov v1 endpoint: %v", err)
-	// }
-	// if validV1Endpoint {
-	// 	msgVote := v1.MsgVote{
-	// 		ProposalId: proposalID,
-	// 		Voter:      valAddr,
-	// 		Option:     voteOption,
-	// 		Metadata:   metadata,
-	// 	}
-	// 	msgAny, err = cdctypes.NewAnyWithValue(&msgVote)
-	// 	if err != nil {
-	// 		return fmt.Errorf("error on converting msg to Any: %v", err)
-	// 	}
+	isV1, err := useGovV1(caps, validEndpoint, granter, keyAddr, v1Msg, v1beta1Msg)
+	if err != nil {
+		return nil, err
+	}
 
-	// } else {
-	msgVote := v1beta1.MsgVote{
-		ProposalId: proposalID,
-		Voter:      granter,
-		Option:     v1beta1.VoteOption(voteOption),
+	return &voteSession{
+		chainClient: chainClient,
+		keyAddr:     keyAddr,
+		endpoint:    validEndpoint,
+		isV1:        isV1,
+		feeGranter:  FeeGranter(validEndpoint, granter, keyAddr),
+	}, nil
+}
+
+// execMsg wraps the votes into an authz MsgExec of the grantee.
+func (s *voteSession) execMsg(msgs ...sdk.Msg) (*authz.MsgExec, error) {
+	var msgAnys []*cdctypes.Any
+	for _, msg := range msgs {
+		msgAny, err := cdctypes.NewAnyWithValue(msg)
+		if err != nil {
+			return nil, fmt.Errorf("error on converting msg to Any: %v", err)
+		}
+		msgAnys = append(msgAnys, msgAny)
 	}
-	msgAny, err = cdctypes.NewAnyWithValue(&msgVote)
+
+	return &authz.MsgExec{
+		Grantee: s.keyAddr,
+		Msgs:    msgAnys,
+	}, nil
+}
+
+// simulate runs the vote through the simulate endpoint of the chain and
+// reports the estimated gas and fee, or the reason the vote would fail.
+func (s *voteSession) simulate(chainName, valAddr string, responseWriter slacker.ResponseWriter, msgs ...sdk.Msg) error {
+	req, err := s.execMsg(msgs...)
 	if err != nil {
-		return "", fmt.Errorf("error on converting msg to Any: %v", err)
+		return err
 	}
-	// }
 
-	req := &authz.MsgExec{
-		Grantee: keyAddr,
-		Msgs:    []*cdctypes.Any{msgAny},
+	sim, err := Simulate(s.chainClient, req)
+	if err != nil {
+		return fmt.Errorf("vote for %s on %s would fail: %v", valAddr, chainName, err)
+	}
+
+	result := fmt.Sprintf("Simulated vote for %s on %s: %s", valAddr, chainName, sim)
+	if s.feeGranter != "" {
+		result += fmt.Sprintf(", paid by the fee allowance of %s", s.feeGranter)
+	}
+
+	responseWriter.Reply(result)
+	return nil
+}
+
+// broadcast executes the vote through authz and confirms it on chain in the
+// background. voteLog must be in the form the chain reports the vote in.
+func (s *voteSession) broadcast(ctx types.Context, chainName, valAddr, pID, voteLog, memo string,
+	msg sdk.Msg, responseWriter slacker.ResponseWriter,
+) (string, error) {
+	res, result, err := s.send(chainName, memo, msg)
+	if err != nil {
+		return "", err
+	}
+
+	s.confirmInBackground(ctx, chainName, valAddr, res.TxHash, result, []pendingVote{{pID, voteLog}}, responseWriter)
+	return result + "\n" + confirmingResult, nil
+}
+
+// send broadcasts the votes in a single authz MsgExec and returns the
+// response along with the link to the transaction.
+func (s *voteSession) send(chainName, memo string, msgs ...sdk.Msg) (*sdk.TxResponse, string, error) {
+	req, err := s.execMsg(msgs...)
+	if err != nil {
+		return nil, "", err
 	}
 
-	responseWriter.Reply(fmt.Sprintf("voting %s on %s proposal %d", voteOption, chainName, proposalID))
 	// Send msg and get response
-	res, err := chainClient.SendMsg(context.Background(), req, memo)
+	res, err := SendMsgs(s.chainClient, []sdk.Msg{req}, memo, s.feeGranter)
 	if err != nil {
 		if res != nil {
-			return "", fmt.Errorf("failed to vote on proposal: code(%d) msg(%s)", res.Code, res.RawLog)
-		}
-		return "", fmt.Errorf("failed to vote.Err: %v", err)
-	} else {
-		if err = ctx.Database().UpdateVoteLog(chainName, pID, vote); err != nil {
-			fmt.Printf("failed to store logs: %v", err)
+			return nil, "", fmt.Errorf("failed to vote on proposal: code(%d) msg(%s)", res.Code, res.RawLog)
 		}
+		return nil, "", fmt.Errorf("failed to vote.Err: %v", err)
 	}
 
 	mintscanName := chainName
@@ -163,71 +353,330 @@ func ExecVote(ctx types.Context, chainName, pID, granter, vote,
 		mintscanName = newName
 	}
 
-	return fmt.Sprintf("Trasaction broadcasted: https://mintscan.io/%s/txs/%s", mintscanName, res.TxHash), nil
+	return res, fmt.Sprintf("Trasaction broadcasted: https://mintscan.io/%s/txs/%s", mintscanName, res.TxHash), nil
 }
 
-// Converts the string to a acceptable vote format
-func stringToVoteOption(str string) (v1.VoteOption, error) {
-	str = strings.ToLower(str)
-	switch str {
-	case "yes":
-		return v1.OptionYes, nil
-	case "no":
-		return v1.OptionNo, nil
-	case "abstain":
-		return v1.OptionAbstain, nil
-	case "no_with_veto":
-		return v1.OptionNoWithVeto, nil
-	default:
-		return v1.VoteOption(0), fmt.Errorf("invalid vote option: %s", str)
+const (
+	// confirmationAttempts is the number of times the tx and the vote are
+	// queried before the vote is reported as not confirmed
+	confirmationAttempts = 10
+	confirmationInterval = 6 * time.Second
+)
 
-	}
+// confirmingResult is added to the result of broadcasted votes, which are
+// confirmed in the background
+const confirmingResult = "Waiting for the vote to land on chain, the confirmation follows in the thread"
+
+// pendingVote is a broadcasted vote on a proposal waiting to be confirmed on
+// chain
+type pendingVote struct {
+	proposalID string
+	voteLog    string
 }
 
-// Gets valid v1 endpoints from Chain registry
-func GetValidV1Endpoint(chainInfo registry.ChainInfo) (bool, error) {
-	var out []string
-	for _, endpoint := range chainInfo.Apis.Rest {
-		u, err := url.Parse(endpoint.Address)
+// confirmInBackground waits for the transaction of the votes and checks that
+// the chain reports each vote, without blocking the caller. The vote logs are
+// only marked confirmed, with the tx hash, once both checks pass. Confirmed
+// votes are posted in the thread of the proposal, or in the thread of the
+// response if the proposal has none, and votes which did not land are
+// reported in the thread of the response.
+func (s *voteSession) confirmInBackground(ctx types.Context, chainName, valAddr, txHash, result string,
+	votes []pendingVote, responseWriter slacker.ResponseWriter,
+) {
+	go func() {
+		txErr := waitForTx(s.chainClient, txHash)
+		for _, vote := range votes {
+			err := txErr
+			if err == nil {
+				err = s.confirmVote(ctx, chainName, valAddr, vote.proposalID, vote.voteLog)
+			}
+			if err != nil {
+				log.Printf("vote of %s on %s proposal %s is not confirmed: %v", valAddr, chainName, vote.proposalID, err)
+				responseWriter.Reply(fmt.Sprintf(":warning: Vote of %s on %s proposal %s did not land on chain: %v", valAddr, chainName, vote.proposalID, err),
+					slacker.WithThreadReply(true))
+				continue
+			}
+
+			if err := ctx.Database().ConfirmVoteLog(chainName, valAddr, vote.proposalID, vote.voteLog, txHash); err != nil {
+				log.Printf("failed to store logs: %v", err)
+			}
+
+			if !notifyVoteConfirmed(ctx, chainName, valAddr, vote.proposalID, vote.voteLog, result) {
+				responseWriter.Reply(fmt.Sprintf(":white_check_mark: Vote *%s* of %s on %s proposal %s confirmed on chain",
+					FormatVoteOption(vote.voteLog), valAddr, chainName, vote.proposalID), slacker.WithThreadReply(true))
+			}
+		}
+	}()
+}
+
+// confirmVote waits for the chain to report voteLog as the vote of the
+// validator.
+func (s *voteSession) confirmVote(ctx types.Context, chainName, valAddr, pID, voteLog string) error {
+	var vote string
+	for i := 0; i < confirmationAttempts; i++ {
+		var err error
+		vote, err = GetValidatorVoteOption(ctx, s.isV1, chainName, s.endpoint, pID, valAddr)
 		if err != nil {
-			return false, err
-		}
-		var port string
-		if u.Port() == "" {
-			switch u.Scheme {
-			case "https":
-				port = "443"
-			case "http":
-				port = "80"
-			default:
-				return false, fmt.Errorf("invalid or unsupported url scheme: %v", u.Scheme)
+			log.Printf("failed to get vote of %s on %s proposal %s: %v", valAddr, chainName, pID, err)
+		} else if vote == voteLog {
+			return nil
+		}
 
+		time.Sleep(confirmationInterval)
+	}
+
+	if vote == "" {
+		return fmt.Errorf("the chain does not report a vote of %s", valAddr)
+	}
+
+	return fmt.Errorf("the chain reports %s as the vote of %s", vote, valAddr)
+}
+
+// waitForTx polls the chain for the result of the transaction and returns an
+// error if it is not found or failed.
+func waitForTx(chainClient *lensclient.ChainClient, txHash string) error {
+	for i := 0; i < confirmationAttempts; i++ {
+		res, err := chainClient.QueryTx(context.Background(), txHash, false)
+		if err == nil {
+			if res.TxResult.Code != 0 {
+				return fmt.Errorf("transaction %s failed: code(%d) msg(%s)", txHash, res.TxResult.Code, res.TxResult.Log)
 			}
-		} else {
-			port = u.Port()
+			return nil
+		}
+
+		time.Sleep(confirmationInterval)
+	}
+
+	return fmt.Errorf("transaction %s was not found on chain", txHash)
+}
+
+// dryRunResult is returned instead of the transaction link for simulated votes
+const dryRunResult = "Dry run: the vote was not broadcasted. Run the command without --dry-run to broadcast it."
+
+// Simulation holds the outcome of simulating a transaction
+type Simulation struct {
+	GasUsed uint64
+	// GasLimit is the gas used, multiplied by the gas adjustment of the client
+	GasLimit uint64
+	Fee      sdk.Coins
+}
+
+func (s Simulation) String() string {
+	return fmt.Sprintf("gas used %d, gas limit %d, estimated fee %s", s.GasUsed, s.GasLimit, s.Fee)
+}
+
+// Simulate runs the messages through the simulate endpoint of the chain,
+// without broadcasting them, and estimates the fee from the gas prices of
+// the client. Missing authz grants or insufficient funds are returned as
+// errors.
+func Simulate(chainClient *lensclient.ChainClient, msgs ...sdk.Msg) (Simulation, error) {
+	txf, err := chainClient.PrepareFactory(chainClient.TxFactory())
+	if err != nil {
+		return Simulation{}, fmt.Errorf("failed to prepare transaction: %v", err)
+	}
+
+	simRes, gasLimit, err := chainClient.CalculateGas(context.Background(), txf, msgs...)
+	if err != nil {
+		return Simulation{}, fmt.Errorf("simulation failed: %v", err)
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(chainClient.Config.GasPrices)
+	if err != nil {
+		return Simulation{}, fmt.Errorf("invalid gas prices %s: %v", chainClient.Config.GasPrices, err)
+	}
+
+	fee := sdk.NewCoins()
+	for _, gasPrice := range gasPrices {
+		amount := gasPrice.Amount.MulInt64(int64(gasLimit)).Ceil().RoundInt()
+		fee = fee.Add(sdk.NewCoin(gasPrice.Denom, amount))
+	}
+
+	return Simulation{
+		GasUsed:  simRes.GasInfo.GasUsed,
+		GasLimit: gasLimit,
+		Fee:      fee,
+	}, nil
+}
+
+// useGovV1 returns true if the vote should be sent as a gov v1 message. The
+// v1 message is used when the chain supports gov v1 and the grantee holds a
+// v1 grant, otherwise the v1beta1 message is used.
+func useGovV1(caps database.ChainCapabilities, endpoint, granter, grantee string, v1Msg, v1beta1Msg sdk.Msg) (bool, error) {
+	if caps.GovV1 {
+		hasV1Grant, err := utils.HasAuthzGrant(endpoint, granter, grantee, sdk.MsgTypeURL(v1Msg))
+		if err != nil {
+			return false, fmt.Errorf("failed to get gov v1 authz grant: %v", err)
+		}
+
+		if hasV1Grant {
+			return true, nil
+		}
+	}
+
+	if caps.GovV1beta1 {
+		hasV1beta1Grant, err := utils.HasAuthzGrant(endpoint, granter, grantee, sdk.MsgTypeURL(v1beta1Msg))
+		if err != nil {
+			return false, fmt.Errorf("failed to get gov v1beta1 authz grant: %v", err)
 		}
 
-		out = append(out, fmt.Sprintf("%s://%s:%s%s", u.Scheme, u.Hostname(), port, u.Path))
+		if hasV1beta1Grant {
+			return false, nil
+		}
 	}
-	validEndpoint := false
-	for _, endpoint := range out {
-		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+
+	msgType := sdk.MsgTypeURL(v1beta1Msg)
+	if caps.GovV1 {
+		msgType = sdk.MsgTypeURL(v1Msg)
+	}
+
+	return false, fmt.Errorf("%s does not have authorization to execute %s on behalf of %s", grantee, msgType, granter)
+}
+
+// ParseWeightedVoteOptions parses comma separated option=weight pairs into
+// weighted vote options. Every option may appear once and the weights must
+// add up to 1.
+func ParseWeightedVoteOptions(str string) (v1.WeightedVoteOptions, error) {
+	var options v1.WeightedVoteOptions
+	seen := make(map[v1.VoteOption]bool)
+	totalWeight := sdk.ZeroDec()
+	for _, pair := range strings.Split(str, ",") {
+		fields := strings.Split(strings.TrimSpace(pair), "=")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid weighted vote option %q, expected option=weight", pair)
+		}
+
+		option, err := stringToVoteOption(fields[0])
 		if err != nil {
-			return false, err
+			return nil, err
 		}
-		client := &http.Client{
-			Timeout: 10 * time.Second, // set the time so that we should get response within that time
+		if seen[option] {
+			return nil, fmt.Errorf("duplicate vote option: %s", fields[0])
 		}
+		seen[option] = true
 
-		resp, err := client.Do(req)
+		weight, err := sdk.NewDecFromStr(fields[1])
 		if err != nil {
-			return false, err
+			return nil, fmt.Errorf("invalid weight %q for option %s: %v", fields[1], fields[0], err)
 		}
-		defer resp.Body.Close()
-		validEndpoint = true
-		if validEndpoint {
-			break
+		if !weight.IsPositive() || weight.GT(sdk.OneDec()) {
+			return nil, fmt.Errorf("weight of option %s must be greater than 0 and at most 1", fields[0])
 		}
+
+		totalWeight = totalWeight.Add(weight)
+		options = append(options, v1.NewWeightedVoteOption(option, weight))
+	}
+
+	if !totalWeight.Equal(sdk.OneDec()) {
+		return nil, fmt.Errorf("weights of vote options must add up to 1, got %s", totalWeight)
+	}
+
+	return options, nil
+}
+
+// FormatWeightedVote renders vote options as comma separated option-weight
+// pairs, the form in which weighted votes are stored in the vote logs.
+func FormatWeightedVote(options []types.VoteOption) string {
+	var pairs []string
+	for _, option := range options {
+		weight := option.Weight
+		if dec, err := sdk.NewDecFromStr(option.Weight); err == nil {
+			weight = dec.String()
+		}
+		pairs = append(pairs, fmt.Sprintf("%s-%s", option.Option, weight))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// GetValidatorVoteOption returns the vote of the validator on the proposal,
+// or an empty string if the validator has not voted yet.
+func GetValidatorVoteOption(ctx types.Context, isV1 bool, chainName, restEndpoint, proposalID, validatorAddress string) (string, error) {
+	accAddrString, err := utils.ConvertValAddrToAccAddr(ctx, validatorAddress, chainName)
+	if err != nil {
+		return "", err
+	}
+
+	if isV1 {
+		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+			Endpoint: restEndpoint + "/cosmos/gov/v1/proposals/" + proposalID + "/votes/" + accAddrString,
+			Method:   http.MethodGet,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		var vote types.VoteResponse
+		if err := json.Unmarshal(resp.Body, &vote); err != nil {
+			return "", err
+		}
+
+		if len(vote.Vote.Options) == 0 {
+			return "", nil
+		}
+
+		if len(vote.Vote.Options) == 1 {
+			return vote.Vote.Options[0].Option, nil
+		}
+
+		return FormatWeightedVote(vote.Vote.Options), nil
+
+	} else {
+		resp, err := endpoints.HitHTTPTarget(types.HTTPOptions{
+			Endpoint: restEndpoint + "/cosmos/gov/v1beta1/proposals/" + proposalID + "/votes/" + accAddrString,
+			Method:   http.MethodGet,
+		})
+		if err != nil {
+			return "", err
+		}
+		var vote types.LegacyVoteResponse
+		if err := json.Unmarshal(resp.Body, &vote); err != nil {
+			return "", err
+		}
+
+		if len(vote.Vote.Options) == 0 {
+			return "", nil
+		}
+
+		if len(vote.Vote.Options) == 1 {
+			return vote.Vote.Options[0].Option, nil
+		}
+
+		return FormatWeightedVote(vote.Vote.Options), nil
+	}
+}
+
+func toVoteOptions(options v1.WeightedVoteOptions) []types.VoteOption {
+	var out []types.VoteOption
+	for _, option := range options {
+		out = append(out, types.VoteOption{
+			Option: option.Option.String(),
+			Weight: option.Weight,
+		})
+	}
+
+	return out
+}
+
+// ValidateVoteOption returns an error if the string is not a vote option
+func ValidateVoteOption(str string) error {
+	_, err := stringToVoteOption(str)
+	return err
+}
+
+// Converts the string to a acceptable vote format
+func stringToVoteOption(str string) (v1.VoteOption, error) {
+	str = strings.ToLower(str)
+	switch str {
+	case "yes":
+		return v1.OptionYes, nil
+	case "no":
+		return v1.OptionNo, nil
+	case "abstain":
+		return v1.OptionAbstain, nil
+	case "no_with_veto":
+		return v1.OptionNoWithVeto, nil
+	default:
+		return v1.VoteOption(0), fmt.Errorf("invalid vote option: %s", str)
+
 	}
-	return validEndpoint, nil
 }
@@ -0,0 +1,69 @@
+package voting
+
+import (
+	"testing"
+
+	v1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeightedVoteOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[v1.VoteOption]string
+		wantErr string
+	}{
+		{
+			name:  "split vote",
+			input: "yes=0.7,abstain=0.3",
+			want:  map[v1.VoteOption]string{v1.OptionYes: "0.700000000000000000", v1.OptionAbstain: "0.300000000000000000"},
+		},
+		{
+			name:  "single option with spaces and upper case",
+			input: " YES=1 ",
+			want:  map[v1.VoteOption]string{v1.OptionYes: "1.000000000000000000"},
+		},
+		{
+			name:  "all options",
+			input: "yes=0.25,no=0.25,abstain=0.25,no_with_veto=0.25",
+			want: map[v1.VoteOption]string{
+				v1.OptionYes: "0.250000000000000000", v1.OptionNo: "0.250000000000000000",
+				v1.OptionAbstain: "0.250000000000000000", v1.OptionNoWithVeto: "0.250000000000000000",
+			},
+		},
+		{name: "weights below 1", input: "yes=0.5,no=0.4", wantErr: "must add up to 1"},
+		{name: "weights above 1", input: "yes=0.7,no=0.4", wantErr: "must add up to 1"},
+		{name: "duplicate option", input: "yes=0.5,yes=0.5", wantErr: "duplicate vote option"},
+		{name: "unknown option", input: "maybe=1", wantErr: "invalid vote option"},
+		{name: "missing weight", input: "yes", wantErr: "expected option=weight"},
+		{name: "extra separator", input: "yes=0.5=0.5", wantErr: "expected option=weight"},
+		{name: "invalid weight", input: "yes=abc", wantErr: "invalid weight"},
+		{name: "zero weight", input: "yes=1,no=0", wantErr: "greater than 0"},
+		{name: "negative weight", input: "yes=1.5,no=-0.5", wantErr: "greater than 0"},
+		{name: "empty", input: "", wantErr: "expected option=weight"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := ParseWeightedVoteOptions(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			got := make(map[v1.VoteOption]string)
+			for _, option := range options {
+				got[option.Option] = option.Weight
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatWeightedVote(t *testing.T) {
+	options, err := ParseWeightedVoteOptions("yes=0.7,abstain=0.3")
+	assert.NoError(t, err)
+	assert.Equal(t, "VOTE_OPTION_YES-0.700000000000000000,VOTE_OPTION_ABSTAIN-0.300000000000000000", FormatWeightedVote(toVoteOptions(options)))
+}